@@ -0,0 +1,29 @@
+Feature: build command
+  Scenario: Print Go source code of a program
+    Given a file named "main.cloe" with:
+    """
+    (def (f x) (+ x 1))
+    (print (f 41))
+    """
+    When I successfully run `cloe build --go main.cloe`
+    Then the stdout should contain "package main"
+    And the stdout should contain "func main()"
+
+  Scenario: Build an executable of a program
+    Given a file named "main.cloe" with:
+    """
+    (def (f x) (+ x 1))
+    (print (f 41))
+    """
+    When I successfully run `cloe build -o hello main.cloe`
+    And I successfully run `./hello`
+    Then the stdout should contain exactly "42"
+
+  Scenario: Fail to build a program with unknown names
+    Given a file named "main.cloe" with:
+    """
+    (print (f 42))
+    """
+    When I run `cloe build --go main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "NameError"
@@ -2,7 +2,7 @@ Feature: clutil command
   Scenario: Install modules in a repository
     When I run the following commands:
     """
-    CLOE_PATH=$PWD/.cloe clutil install https://github.com/cloe-lang/examples
+    HOME=$PWD clutil install https://github.com/cloe-lang/examples
     """
     Then I run the following commands:
     """
@@ -14,7 +14,7 @@ Feature: clutil command
   Scenario: Clean up a language directory
     Given I run the following commands:
     """
-    CLOE_PATH=$PWD/.cloe clutil install https://github.com/cloe-lang/examples
+    HOME=$PWD clutil install https://github.com/cloe-lang/examples
     """
     And I run the following commands:
     """
@@ -23,7 +23,7 @@ Feature: clutil command
     And the exit status should be 0
     When I run the following commands:
     """
-    CLOE_PATH=$PWD/.cloe clutil clean
+    HOME=$PWD clutil clean
     """
     Then I run the following commands:
     """
@@ -55,3 +55,35 @@ Feature: Collections
     """
     ["C" "l" "o" "e" " " "i" "s" " " "g" "o" "o" "d" "."]
     """
+
+  Scenario: Implement custom collections
+    Given a file named "main.cloe" with:
+    """
+    (def (queue ..xs)
+      (implement "queue"
+        . index (\ (i) (@ xs i))
+          insert (\ (i x) (queue ..(insert xs i x)))
+          size (\ () (size xs))
+          toList (\ () xs)
+          toString (\ () $"queue{xs}")
+          compare (\ (q) (if (< xs (toList q)) -1 (= xs (toList q)) 0 1))))
+
+    (let q (insert (queue 1 2) 3 3))
+
+    (print (@ q 3) (size q) (toList q) (typeOf q))
+    (print (toString q) (< (queue 1) q) (= q (queue 1 2 3)))
+    (print (sort [(queue 2) (queue 1)]))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain "3 3 [1 2 3] queue"
+    And the stdout should contain "queue[1 2 3] true true"
+    And the stdout should contain "[queue[1] queue[2]]"
+
+  Scenario: Use custom values without implementations
+    Given a file named "main.cloe" with:
+    """
+    (print (size (implement "foo")))
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "foo does not implement size"
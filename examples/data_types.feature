@@ -21,6 +21,64 @@ Feature: Data types
     """
     Then I successfully run `cloe main.cloe`
 
+  Scenario: Use raw and multi-line string literals
+    Given a file named "main.cloe" with:
+    ```
+    (import "re")
+
+    (print (re.match `^\d+(\.\d+)?$` "3.14"))
+    (let html """
+      <ul>
+        <li class="item">foo</li>
+      </ul>
+      """)
+    (print html . end "")
+    ```
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain "true"
+    And the stdout should contain:
+    """
+    <ul>
+      <li class="item">foo</li>
+    </ul>
+    """
+
+  Scenario: Define record types
+    Given a file named "main.cloe" with:
+    """
+    (deftype shape (circle radius) (rectangle width height))
+
+    (let c (circle 2))
+    (print (@ c "radius") (typeOf c) (dump (rectangle 3 "4")))
+    (print (= c (circle 2)) (= c (circle 3)) (= c {"radius" 2}))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain "2 shape (rectangle 3 \"4\")"
+    And the stdout should contain "true false false"
+
+  Scenario: Access missing fields of records
+    Given a file named "main.cloe" with:
+    """
+    (deftype point (point x y))
+
+    (print (@ (point 1 2) "z"))
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "KeyNotFoundError"
+
+  Scenario: Interpolate expressions into strings
+    Given a file named "main.cloe" with:
+    """
+    (let name "Bob")
+    (let n 3)
+    (print $"Hello, {name}! You have {(+ n 1)} items in \{cart\}.")
+    (print $"{[1 "two"]} {$"{n}"}")
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain "Hello, Bob! You have 4 items in {cart}."
+    And the stdout should contain "[1 \"two\"] 3"
+
   Scenario: Expand dictionaries into a dictionary
     Given a file named "main.cloe" with:
     """
@@ -43,3 +43,42 @@ Feature: Errors
     """
     When I successfully run `cloe main.cloe`
     Then the stdout should contain "FooError"
+
+  Scenario: Collapse repeated frames in an error
+    Given a file named "main.cloe" with:
+    """
+    (def (f n)
+      (if (= n 0) (error "MyError" "bottom") (+ 1 (f (- n 1)))))
+
+    (print (f 100))
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "main.cloe:2:42:"
+    And the stderr should contain "(previous frame repeated 99 times)"
+    And the stderr should contain "MyError: bottom"
+
+  Scenario: Print an error in JSON
+    Given a file named "main.cloe" with:
+    """
+    (print (+ 1 true))
+    """
+    When I run `cloe --error-format json main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "{\"name\":\"TypeError\""
+    And the stderr should contain "\"column\":8"
+
+  Scenario: Report all syntax errors in a file
+    Given a file named "main.cloe" with:
+    """
+    (print 1))
+    (let x)
+    (print "foo")
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "main.cloe:1:10:"
+    And the stderr should contain "SyntaxError: unexpected `)`"
+    And the stderr should contain "main.cloe:2:7:"
+    And the stderr should contain "SyntaxError: expected expression"
+    And the stdout should not contain "foo"
@@ -0,0 +1,35 @@
+Feature: fmt command
+  Scenario: Format a file
+    Given a file named "main.cloe" with:
+    """
+    ; Greet someone.
+    (def (greet name)   (merge "Hello, " name "!")) ; trailing comment
+    (print  (greet "world"))
+    """
+    When I successfully run `cloe fmt main.cloe`
+    Then the file "main.cloe" should contain exactly:
+    """
+    ; Greet someone.
+    (def (greet name) (merge "Hello, " name "!")) ; trailing comment
+    (print (greet "world"))
+
+    """
+
+  Scenario: Check if a file is formatted
+    Given a file named "main.cloe" with:
+    """
+    (print  42)
+    """
+    When I run `cloe fmt --check main.cloe`
+    Then the exit status should not be 0
+    And the stdout should contain "-(print  42)"
+    And the stdout should contain "+(print 42)"
+
+  Scenario: Check a formatted file
+    Given a file named "main.cloe" with:
+    """
+    (print 42)
+
+    """
+    When I successfully run `cloe fmt --check main.cloe`
+    Then the stdout should contain exactly ""
@@ -44,6 +44,33 @@ Feature: Functions
     42
     """
 
+  Scenario: Pass required keyword arguments
+    Given a file named "main.cloe" with:
+    """
+    (def (request url . !timeout retries 3) [url timeout retries])
+
+    (seq!
+      (print (request "foo" . timeout 10))
+      (print (request "foo" . retries 1 ..{"timeout" 5})))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly:
+    """
+    ["foo" 10 3]
+    ["foo" 5 1]
+    """
+
+  Scenario: Miss a required keyword argument
+    Given a file named "main.cloe" with:
+    """
+    (def (request url . !timeout) [url timeout])
+
+    (print (request "foo"))
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "ArgumentError: missing keyword argument `timeout`"
+
   Scenario: Apply a function to complex arguments
     Given a file named "main.cloe" with:
     """
@@ -169,3 +196,34 @@ Feature: Functions
     """
     When I successfully run `cloe main.cloe`
     Then the stdout should contain exactly "Hello, world!"
+
+  Scenario: Thread a value as first arguments
+    Given a file named "main.cloe" with:
+    """
+    (print (-> 10 (- 3) (/ 7) toString))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "1"
+
+  Scenario: Thread a value as last arguments
+    Given a file named "main.cloe" with:
+    """
+    (print (->> [3 1 2]
+                (sort . less >)
+                (map (\ (x) (* x 10)))
+                (filter (\ (x) (> x 10)))))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "[30 20]"
+
+  Scenario: Point at a failed step in a threading form
+    Given a file named "main.cloe" with:
+    """
+    (print (-> "foo"
+               (+ "bar")
+               (+ 42)))
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "main.cloe:3:"
+    And the stderr should contain "TypeError"
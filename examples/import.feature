@@ -98,6 +98,146 @@ Feature: Import statement
     When I successfully run `cloe main.cloe`
     Then the stdout should contain exactly "Hello, world!"
 
+  Scenario: Import members of a module selectively
+    Given a file named "main.cloe" with:
+    """
+    (import "re" (match . as re-match find))
+    (import "./mod" (hello . as greet))
+
+    (seq!
+      (print (re-match "o" "foo"))
+      (print (find "o+" "foo"))
+      (greet "world"))
+    """
+    And a file named "mod.cloe" with:
+    """
+    (def (hello name) (print (merge "Hello, " name "!")))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly:
+    """
+    true
+    ["oo"]
+    Hello, world!
+    """
+
+  Scenario: Import members not exported by a module
+    Given a file named "main.cloe" with:
+    """
+    (import "./mod" (bye))
+    """
+    And a file named "mod.cloe" with:
+    """
+    (def (hello name) (print (merge "Hello, " name "!")))
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "bye is not exported"
+
+  Scenario: Import exported members of a module
+    Given a file named "main.cloe" with:
+    """
+    (import "./mod")
+
+    (print (mod.double 21))
+    """
+    And a file named "mod.cloe" with:
+    """
+    (export double)
+
+    (def (twice x) (* 2 x))
+    (def (double x) (twice x))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "42"
+
+  Scenario: Import private members of a module
+    Given a file named "main.cloe" with:
+    """
+    (import "./mod")
+
+    (print (mod._twice 21))
+    """
+    And a file named "mod.cloe" with:
+    """
+    (def (_twice x) (* 2 x))
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "mod._twice is private"
+
+  Scenario: Import modules cyclically
+    Given a file named "main.cloe" with:
+    """
+    (import "./foo")
+    """
+    And a file named "foo.cloe" with:
+    """
+    (import "./bar")
+    """
+    And a file named "bar.cloe" with:
+    """
+    (import "./foo")
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "import cycle: foo.cloe -> bar.cloe -> foo.cloe"
+
+  Scenario: Import data files
+    Given a file named "main.cloe" with:
+    """
+    (import "./config/app.json")
+    (import "./banner.txt" . as banner)
+
+    (seq!
+      (print banner)
+      (print (@ app "name") (@ app "ports")))
+    """
+    And a file named "config/app.json" with:
+    """
+    {"name": "server", "ports": [80, 443]}
+    """
+    And a file named "banner.txt" with:
+    """
+    Welcome!
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain "Welcome!"
+    And the stdout should contain "server [80 443]"
+
+  Scenario: Import a data file in a sub module
+    Given a file named "main.cloe" with:
+    """
+    (import "./lib/mod")
+
+    (print mod.name)
+    """
+    And a file named "lib/mod.cloe" with:
+    """
+    (import "./config.json")
+
+    (let name (@ config "name"))
+    """
+    And a file named "lib/config.json" with:
+    """
+    {"name": "server"}
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "server"
+
+  Scenario: Import an invalid JSON file
+    Given a file named "main.cloe" with:
+    """
+    (import "./config.json")
+    """
+    And a file named "config.json" with:
+    """
+    {"name": }
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "ImportError"
+
   Scenario: Import a module with invalid path
     Given a file named "main.cloe" with:
     """
@@ -155,3 +295,82 @@ Feature: Import statement
     """
     When I successfully run `sh main.sh`
     Then the stdout should contain exactly "Hello, world!"
+
+  Scenario: Import modules via multiple directories in language path
+    Given a file named "main.cloe" with:
+    """
+    (import "foo")
+    (import "bar")
+
+    (seq! (foo.hello) (bar.hello))
+    """
+    And a file named "modules1/foo.cloe" with:
+    """
+    (def (hello) (print "foo"))
+    """
+    And a file named "modules2/foo.cloe" with:
+    """
+    (def (hello) (print "shadowed"))
+    """
+    And a file named "modules2/bar.cloe" with:
+    """
+    (def (hello) (print "bar"))
+    """
+    And a file named "main.sh" with:
+    """
+    CLOE_PATH=$PWD/modules1:$PWD/modules2 cloe main.cloe
+    """
+    When I successfully run `sh main.sh`
+    Then the stdout should contain exactly:
+    """
+    foo
+    bar
+    """
+
+  Scenario: Import a vendored module in precedence to language path
+    Given a file named "main.cloe" with:
+    """
+    (import "foo")
+
+    (foo.hello)
+    """
+    And a file named "vendor/foo.cloe" with:
+    """
+    (def (hello) (print "vendored"))
+    """
+    And a file named "modules/foo.cloe" with:
+    """
+    (def (hello) (print "installed"))
+    """
+    And a file named "main.sh" with:
+    """
+    CLOE_PATH=$PWD/modules cloe main.cloe
+    """
+    When I successfully run `sh main.sh`
+    Then the stdout should contain exactly "vendored"
+
+  Scenario: Import a module installed in language directory
+    Given a file named "main.cloe" with:
+    """
+    (import "example.com/foo")
+
+    (foo.hello)
+    """
+    And a file named ".cloe/src/example.com/foo.cloe" with:
+    """
+    (def (hello) (print "Hello, world!"))
+    """
+    And a file named "main.sh" with:
+    """
+    HOME=$PWD cloe main.cloe
+    """
+    When I successfully run `sh main.sh`
+    Then the stdout should contain exactly "Hello, world!"
+
+  Scenario: Print a path where a module is imported from
+    Given a file named "vendor/foo.cloe" with:
+    """
+    (def (hello) (print "Hello, world!"))
+    """
+    When I successfully run `cloe --print-import-path foo`
+    Then the stdout should contain "vendor/foo.cloe"
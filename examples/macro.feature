@@ -0,0 +1,65 @@
+Feature: Macro
+  Scenario: Define and use macros
+    Given a file named "main.cloe" with:
+    """
+    (defmacro (unless c ..body)
+      ["if" c "nil" ["seq" ..body]])
+
+    (print (unless false "foo"))
+    (print (unless true "bar"))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain "foo"
+    And the stdout should contain "nil"
+    And the stdout should not contain "bar"
+
+  Scenario: Expand macros recursively
+    Given a file named "main.cloe" with:
+    """
+    (defmacro (my-and ..xs)
+      (match xs
+        [] "true"
+        [x] x
+        [x ..xs] ["if" x ["my-and" ..xs] "false"]))
+
+    (print (my-and true true 42))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "42"
+
+  Scenario: Import macros
+    Given a file named "main.cloe" with:
+    """
+    (import "./mod")
+    (mod.when true (print "foo"))
+    """
+    And a file named "mod.cloe" with:
+    """
+    (defmacro (when c ..body)
+      ["if" c ["seq" ..body] "nil"])
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "foo"
+
+  Scenario: Keep names of callers
+    Given a file named "main.cloe" with:
+    """
+    (defmacro (let-tmp v body)
+      (let tmp (gensym))
+      [{"parameters" [tmp] "body" body} v])
+
+    (let tmp "foo")
+    (print (let-tmp 42 tmp))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "foo"
+
+  Scenario: Report errors of macros
+    Given a file named "main.cloe" with:
+    """
+    (defmacro (foo x) {"foo" x})
+    (print (foo 1))
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "MacroError"
@@ -207,3 +207,120 @@ Feature: Match expression
     """
     When I successfully run `cloe main.cloe`
     Then the stdout should contain exactly "[1 1]"
+
+  Scenario: Use guards
+    Given a file named "main.cloe" with:
+    """
+    (def (sign x)
+      (match x
+        n | (> n 0) "positive"
+        n | (< n 0) "negative"
+        _ "zero"))
+
+    (print (sign 42) (sign -42) (sign 0))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "positive negative zero"
+
+  Scenario: Fall through to next cases when guards fail
+    Given a file named "main.cloe" with:
+    """
+    (def (first-positive xs)
+      (match xs
+        [x ..xs] | (> x 0) x
+        [_ ..xs] (first-positive xs)
+        [] nil))
+
+    (print (first-positive [-1 0 3 4]) (first-positive [-1]))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "3 nil"
+
+  Scenario: Use or-patterns
+    Given a file named "main.cloe" with:
+    """
+    (def (route request)
+      (match request
+        [(or "GET" "HEAD") path] | (= path "/") "index"
+        [(or "GET" "HEAD") _] "page"
+        (or ["POST" _] ["PUT" _]) "write"
+        _ "unknown"))
+
+    (print
+      (route ["GET" "/"])
+      (route ["HEAD" "/foo"])
+      (route ["PUT" "/foo"])
+      (route ["DELETE" "/foo"]))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "index page write unknown"
+
+  Scenario: Reject or-patterns whose alternatives bind different names
+    Given a file named "main.cloe" with:
+    """
+    (print (match [42] (or [a] b) a))
+    """
+    When I run `cloe main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "must bind the same names"
+
+  Scenario: Use constructor patterns
+    Given a file named "main.cloe" with:
+    """
+    (deftype shape (circle radius) (rectangle width height) (dot))
+
+    (def (area s)
+      (match s
+        (circle r) (* 3 r r)
+        (rectangle w h) | (= w h) (* w w)
+        (rectangle w h) (* w h)
+        (dot) 0
+        _ "not a shape"))
+
+    (print
+      (area (circle 2))
+      (area (rectangle 2 3))
+      (area (rectangle 3 3))
+      (area (dot))
+      (area {"radius" 2}))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "12 6 9 0 not a shape"
+
+  Scenario: Use constructor patterns of imported types
+    Given a file named "shape.cloe" with:
+    """
+    (deftype shape (circle radius) (square side))
+    """
+    And a file named "main.cloe" with:
+    """
+    (import "./shape")
+
+    (print (match [(shape.square 3)] [(shape.circle r)] r [(shape.square a)] a))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "3"
+
+  Scenario: Distinguish types of the same names in different modules
+    Given a file named "a.cloe" with:
+    """
+    (deftype shape (circle r))
+
+    (def (area s)
+      (match s
+        (circle r) (* 3 r r)
+        _ "not a shape"))
+    """
+    And a file named "b.cloe" with:
+    """
+    (deftype shape (circle r))
+    """
+    And a file named "main.cloe" with:
+    """
+    (import "./a")
+    (import "./b")
+
+    (print (a.area (a.circle 2)) (a.area (b.circle 2)) (= (a.circle 1) (b.circle 1)))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "12 not a shape false"
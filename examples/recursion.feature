@@ -187,3 +187,14 @@ Feature: Recursion
     false
     true
     """
+
+  Scenario: Catch an error of too deep recursion
+    Given a file named "main.cloe" with:
+    """
+    (def (f n)
+      (if (= n 0) 0 (+ 1 (f (- n 1)))))
+
+    (print (@ (catch (f 10000000)) "name"))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "RecursionError"
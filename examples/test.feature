@@ -0,0 +1,35 @@
+Feature: test command
+  Scenario: Run passing tests
+    Given a file named "math_test.cloe" with:
+    """
+    (import "test")
+
+    (test.assertEqual (+ 1 2) 3)
+    (test.assertError (error "MyError" "oops") "MyError")
+    (test.assertThrowsNothing [1 2 3])
+    """
+    When I successfully run `cloe test`
+    Then the stdout should contain "PASS"
+    And the stdout should contain "3 passed, 0 failed"
+
+  Scenario: Run failing tests
+    Given a file named "math_test.cloe" with:
+    """
+    (import "test")
+
+    (test.assertEqual (+ 1 2) 3)
+    (test.assertEqual (+ 1 2) 4)
+    """
+    When I run `cloe test math_test.cloe`
+    Then the exit status should not be 0
+    And the stdout should contain "math_test.cloe:4:"
+    And the stdout should contain "AssertionError"
+    And the stdout should contain "1 passed, 1 failed"
+
+  Scenario: Ignore files which are not tests
+    Given a file named "main.cloe" with:
+    """
+    (print 42)
+    """
+    When I successfully run `cloe test`
+    Then the stdout should contain exactly "0 passed, 0 failed"
@@ -0,0 +1,84 @@
+Feature: Type annotations
+  Scenario: Run a program with type annotations
+    Given a file named "main.cloe" with:
+    """
+    (def (greet name:string . greeting:string "Hello") :string
+      (merge greeting ", " name "!"))
+
+    (def (sum ..xs:list[number]) :number
+      (+ ..xs))
+
+    (print (greet "world") (sum 1 2 3))
+    """
+    When I successfully run `cloe main.cloe`
+    Then the stdout should contain exactly "Hello, world! 6\n"
+
+  Scenario: Check types statically
+    Given a file named "main.cloe" with:
+    """
+    (def (half x:number|nil) :number|nil
+      (if (= x nil) nil (/ x 2)))
+
+    (def (names people:list[dictionary[string string]]) :list[string]
+      (map (\ (p) (@ p "name")) people))
+
+    (print (half 42) (names [{"name" "Alice"}]))
+    """
+    When I successfully run `cloe typecheck main.cloe`
+    Then the stdout should contain exactly ""
+
+  Scenario: Report arguments of wrong types
+    Given a file named "main.cloe" with:
+    """
+    (def (double x:number) (* 2 x))
+
+    (print (double "foo"))
+    """
+    When I run `cloe typecheck main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "main.cloe:3:8:"
+    And the stderr should contain "TypeError: argument 1 of double must be number but got string"
+
+  Scenario: Report mismatches against built-in functions
+    Given a file named "main.cloe" with:
+    """
+    (def (f x:string) (+ x 1))
+    """
+    When I run `cloe typecheck main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "TypeError: argument 1 of + must be number but got string"
+
+  Scenario: Report results of wrong types
+    Given a file named "main.cloe" with:
+    """
+    (def (f x:number) :string (+ x 1))
+    """
+    When I run `cloe typecheck main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "TypeError: function f returns number instead of string"
+
+  Scenario: Check types of imported modules
+    Given a file named "main.cloe" with:
+    """
+    (import "./mod")
+    (import "re")
+
+    (print (mod.double (re.match "a" "abc")))
+    """
+    And a file named "mod.cloe" with:
+    """
+    (def (double x:number) (* 2 x))
+    """
+    When I run `cloe typecheck main.cloe`
+    Then the exit status should not be 0
+    And the stderr should contain "TypeError: argument 1 of mod.double must be number but got boolean"
+
+  Scenario: Leave unannotated code dynamically typed
+    Given a file named "main.cloe" with:
+    """
+    (def (f x) (+ x 1))
+
+    (print (f "foo"))
+    """
+    When I successfully run `cloe typecheck main.cloe`
+    Then the stdout should contain exactly ""
@@ -4,12 +4,18 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime/pprof"
 	"strings"
 
 	"github.com/cloe-lang/cloe/src/lib/compile"
+	"github.com/cloe-lang/cloe/src/lib/consts"
 	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/format"
+	"github.com/cloe-lang/cloe/src/lib/lsp"
+	"github.com/cloe-lang/cloe/src/lib/modules"
 	"github.com/cloe-lang/cloe/src/lib/run"
 	"github.com/docopt/docopt-go"
 )
@@ -17,6 +23,18 @@ import (
 func main() {
 	args := getArgs()
 
+	switch args["--error-format"] {
+	case "json":
+		debug.ErrorFormat = debug.JSONFormat
+	case "text":
+		if isTerminal(os.Stderr) {
+			debug.ErrorFormat = debug.ColoredTextFormat
+		}
+	default:
+		printToStderr("invalid error format: " + fmt.Sprint(args["--error-format"]))
+		os.Exit(1)
+	}
+
 	if args["--debug"].(bool) {
 		debug.Debug = true
 	} else {
@@ -24,7 +42,7 @@ func main() {
 			if r := recover(); r != nil {
 				switch x := r.(type) {
 				case error:
-					printToStderr(x.Error())
+					printError(x)
 				case string:
 					printToStderr(x)
 				default:
@@ -50,6 +68,108 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
+	if n, ok := args["--print-import-path"].(string); ok {
+		if _, ok := modules.Modules[n]; ok {
+			fmt.Printf("%s (built-in)\n", n)
+			return
+		}
+
+		f, err := compile.ModuleFile(n, ".")
+
+		if err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		fmt.Println(f)
+		return
+	}
+
+	if args["fmt"].(bool) {
+		if !formatFiles(args["<file>"].([]string), args["--check"].(bool)) {
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if args["lsp"].(bool) {
+		if err := lsp.Serve(os.Stdin, os.Stdout); err != nil {
+			panic(err)
+		}
+
+		return
+	}
+
+	if args["test"].(bool) {
+		ps := args["<path>"].([]string)
+
+		if len(ps) == 0 {
+			ps = []string{"."}
+		}
+
+		ok, err := run.Test(ps, os.Stdout)
+
+		if err != nil {
+			panic(err)
+		} else if !ok {
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if args["build"].(bool) {
+		p := args["<filename>"].(string)
+
+		if args["--go"].(bool) {
+			s, err := compile.GenerateGo(p)
+
+			if err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(s)
+			return
+		}
+
+		o, _ := args["--output"].(string)
+
+		if err := build(p, o); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if args["check"].(bool) {
+		if _, err := compile.Compile(args["<filename>"].(string)); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if args["typecheck"].(bool) {
+		if err := compile.Typecheck(args["<filename>"].(string)); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if p := args["<filename>"].(string); p == "" && isTerminal(os.Stdin) {
+		if err := run.REPL(os.Stdin, os.Stdout); err != nil {
+			panic(err)
+		}
+
+		return
+	}
+
 	es, err := compile.Compile(args["<filename>"].(string))
 
 	if err != nil {
@@ -63,11 +183,23 @@ func getArgs() map[string]interface{} {
 	usage := `Cloe interpreter
 
 Usage:
-  cloe [-d] [-p <filename>] [<filename>]
+  cloe build [-g] [-o <output>] [--error-format <format>] <filename>
+  cloe check [--error-format <format>] <filename>
+  cloe fmt [--check] <file>...
+  cloe lsp
+  cloe --print-import-path <name> [--error-format <format>]
+  cloe test [<path>...]
+  cloe typecheck [--error-format <format>] <filename>
+  cloe [-d] [-p <filename>] [--error-format <format>] [<filename>]
 
 Options:
+  -c, --check  Print differences instead of formatting files.
   -d, --debug  Turn on debug mode.
+  --error-format <format>  Print errors in text or json. [default: text]
+  -g, --go  Print Go source code instead of building an executable.
+  -o, --output <output>  Write an executable to a file.
   -p, --profile <filename>  Turn on profiling.
+  --print-import-path <name>  Print a path of a module file imported by a name.
   -h, --help  Show this help.`
 
 	args, err := docopt.ParseArgs(usage, os.Args[1:], "0.1.0")
@@ -82,6 +214,61 @@ Options:
 	return args
 }
 
+// formatFiles formats files in place or prints differences of them if check is
+// true. It returns false if any file is not formatted.
+func formatFiles(fs []string, check bool) bool {
+	ok := true
+
+	for _, f := range fs {
+		bs, err := ioutil.ReadFile(f)
+
+		if err != nil {
+			panic(err)
+		}
+
+		s, err := format.Format(f, string(bs))
+
+		if err != nil {
+			panic(err)
+		} else if s == string(bs) {
+			continue
+		} else if check {
+			fmt.Print(format.Diff(f, string(bs), s))
+			ok = false
+			continue
+		}
+
+		i, err := os.Stat(f)
+
+		if err != nil {
+			panic(err)
+		} else if err := ioutil.WriteFile(f, []byte(s), i.Mode()); err != nil {
+			panic(err)
+		}
+	}
+
+	return ok
+}
+
+// build builds an executable of a main module. Its name is the module's one
+// without an extension by default.
+func build(p, o string) error {
+	if o == "" {
+		o = strings.TrimSuffix(filepath.Base(p), consts.FileExtension)
+	}
+
+	return compile.BuildGo(p, o)
+}
+
+func isTerminal(f *os.File) bool {
+	i, err := f.Stat()
+	return err == nil && i.Mode()&os.ModeCharDevice != 0
+}
+
+func printError(err error) {
+	printToStderr(debug.Render(err, debug.ErrorFormat))
+}
+
 func printToStderr(s string) {
-	fmt.Fprintf(os.Stderr, strings.TrimSpace(s)+"\n")
+	fmt.Fprintln(os.Stderr, strings.TrimSpace(s))
 }
@@ -6,6 +6,8 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+
+	"github.com/cloe-lang/cloe/src/lib/consts"
 )
 
 type installer struct {
@@ -30,7 +32,7 @@ func newInstaller(s string) (installer, error) {
 	return installer{
 		u,
 		d,
-		filepath.Join(d, "src", u.Hostname(), filepath.FromSlash(u.Path)),
+		filepath.Join(d, consts.SourceDirectory, u.Hostname(), filepath.FromSlash(u.Path)),
 	}, nil
 }
 
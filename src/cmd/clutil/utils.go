@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/compile"
 )
 
 func mkdirp(d string) error {
@@ -22,13 +22,13 @@ func mkdirp(d string) error {
 }
 
 func getLanguageDirectory() (string, error) {
-	d := os.Getenv(consts.PathName)
+	d, err := compile.LanguageDirectory()
 
-	if d == "" {
-		return "", fmt.Errorf("%v environment variable is not set", consts.PathName)
+	if err != nil {
+		return "", err
 	}
 
-	err := mkdirp(d)
+	err = mkdirp(d)
 
 	if err != nil {
 		return "", err
@@ -0,0 +1,38 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// DefMacro represents a def-macro statement node in ASTs. Its function is
+// run at compile time.
+type DefMacro struct {
+	function DefFunction
+}
+
+// NewDefMacro creates a DefMacro from a function converting ASTs.
+func NewDefMacro(f DefFunction) DefMacro {
+	return DefMacro{f}
+}
+
+// Name returns a name of a macro.
+func (m DefMacro) Name() string {
+	return m.function.Name()
+}
+
+// Function returns a function converting ASTs of a macro.
+func (m DefMacro) Function() DefFunction {
+	return m.function
+}
+
+// DebugInfo returns debug information of a macro.
+func (m DefMacro) DebugInfo() *debug.Info {
+	return m.function.DebugInfo()
+}
+
+func (m DefMacro) String() string {
+	return "(defmacro" + strings.TrimPrefix(fmt.Sprint(m.function), "(def")
+}
@@ -0,0 +1,71 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// DefType represents a definition of a user-defined type with its
+// constructors.
+type DefType struct {
+	name         string
+	constructors []Constructor
+	info         *debug.Info
+}
+
+// NewDefType creates a DefType from a type name and its constructors.
+func NewDefType(n string, cs []Constructor, i *debug.Info) DefType {
+	return DefType{n, cs, i}
+}
+
+// Name returns a name of a type.
+func (t DefType) Name() string {
+	return t.name
+}
+
+// Constructors returns constructors of a type.
+func (t DefType) Constructors() []Constructor {
+	return t.constructors
+}
+
+// DebugInfo returns debug information of a type definition.
+func (t DefType) DebugInfo() *debug.Info {
+	return t.info
+}
+
+func (t DefType) String() string {
+	ss := make([]string, 0, len(t.constructors))
+
+	for _, c := range t.constructors {
+		ss = append(ss, c.String())
+	}
+
+	return fmt.Sprintf("(deftype %v %v)", t.name, strings.Join(ss, " "))
+}
+
+// Constructor represents a constructor of records with its field names.
+type Constructor struct {
+	name   string
+	fields []string
+}
+
+// NewConstructor creates a Constructor.
+func NewConstructor(n string, fs []string) Constructor {
+	return Constructor{n, fs}
+}
+
+// Name returns a name of a constructor.
+func (c Constructor) Name() string {
+	return c.name
+}
+
+// Fields returns field names of records constructed by a constructor.
+func (c Constructor) Fields() []string {
+	return c.fields
+}
+
+func (c Constructor) String() string {
+	return "(" + strings.Join(append([]string{c.name}, c.fields...), " ") + ")"
+}
@@ -1,16 +1,21 @@
 package ast
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
 
 // Effect represents effects of programs.
 type Effect struct {
 	expr     interface{}
 	expanded bool
+	info     *debug.Info
 }
 
 // NewEffect creates an Effect.
-func NewEffect(expr interface{}, expanded bool) Effect {
-	return Effect{expr, expanded}
+func NewEffect(expr interface{}, expanded bool, i *debug.Info) Effect {
+	return Effect{expr, expanded, i}
 }
 
 // Expr returns an expression of the effect.
@@ -23,6 +28,11 @@ func (o Effect) Expanded() bool {
 	return o.expanded
 }
 
+// DebugInfo returns debug information of the effect.
+func (o Effect) DebugInfo() *debug.Info {
+	return o.info
+}
+
 func (o Effect) String() string {
 	if o.expanded {
 		return fmt.Sprintf("..%v", o.expr)
@@ -0,0 +1,33 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// Export represents an export statement listing names exported by a module.
+type Export struct {
+	names []string
+	info  *debug.Info
+}
+
+// NewExport creates an Export.
+func NewExport(ns []string, i *debug.Info) Export {
+	return Export{ns, i}
+}
+
+// Names returns names exported by a module.
+func (e Export) Names() []string {
+	return e.names
+}
+
+// DebugInfo returns debug information of an export statement.
+func (e Export) DebugInfo() *debug.Info {
+	return e.info
+}
+
+func (e Export) String() string {
+	return fmt.Sprintf("(export %v)", strings.Join(e.names, " "))
+}
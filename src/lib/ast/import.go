@@ -2,7 +2,10 @@ package ast
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
+	"github.com/cloe-lang/cloe/src/lib/consts"
 	"github.com/cloe-lang/cloe/src/lib/debug"
 )
 
@@ -10,12 +13,14 @@ import (
 type Import struct {
 	path   string
 	prefix string
+	names  []ImportedName
 	info   *debug.Info
 }
 
-// NewImport creates an Import.
-func NewImport(path, prefix string, info *debug.Info) Import {
-	return Import{path, prefix, info}
+// NewImport creates an Import. All members are imported with a prefix if
+// names are nil. Contents of data files are imported as the prefix.
+func NewImport(path, prefix string, names []ImportedName, info *debug.Info) Import {
+	return Import{path, prefix, names, info}
 }
 
 // Path returns a path to an imported sub module.
@@ -28,6 +33,99 @@ func (i Import) Prefix() string {
 	return i.prefix
 }
 
+// Names returns names of members imported selectively. It returns nil if all
+// members are imported.
+func (i Import) Names() []ImportedName {
+	return i.names
+}
+
+// Data checks if an import is of a data file.
+func (i Import) Data() bool {
+	return IsDataFile(i.path)
+}
+
+// LocalName returns a name of a member in an importing module and if the
+// member is imported.
+func (i Import) LocalName(n string) (string, bool) {
+	if i.Data() {
+		return i.prefix, n == consts.Names.DataMember
+	}
+
+	if i.names == nil {
+		if i.prefix == "" {
+			return n, true
+		}
+
+		return i.prefix + "." + n, true
+	}
+
+	for _, m := range i.names {
+		if m.name == n {
+			return m.alias, true
+		}
+	}
+
+	return "", false
+}
+
+// DebugInfo returns debug information of an import statement.
+func (i Import) DebugInfo() *debug.Info {
+	return i.info
+}
+
 func (i Import) String() string {
-	return fmt.Sprintf("(import %v)", i.path)
+	if i.Data() {
+		return fmt.Sprintf("(import %v . as %v)", i.path, i.prefix)
+	} else if i.names == nil {
+		return fmt.Sprintf("(import %v)", i.path)
+	}
+
+	ss := make([]string, 0, len(i.names))
+
+	for _, n := range i.names {
+		ss = append(ss, n.String())
+	}
+
+	return fmt.Sprintf("(import %v (%v))", i.path, strings.Join(ss, " "))
+}
+
+// ImportedName represents a member imported selectively with its name in an
+// importing module.
+type ImportedName struct {
+	name  string
+	alias string
+}
+
+// NewImportedName creates an ImportedName.
+func NewImportedName(name, alias string) ImportedName {
+	return ImportedName{name, alias}
+}
+
+// Name returns a name of a member in an imported module.
+func (n ImportedName) Name() string {
+	return n.name
+}
+
+// Alias returns a name of a member in an importing module.
+func (n ImportedName) Alias() string {
+	return n.alias
+}
+
+func (n ImportedName) String() string {
+	if n.name == n.alias {
+		return n.name
+	}
+
+	return n.name + " . as " + n.alias
+}
+
+// IsDataFile checks if a path of an imported file refers to a data file whose
+// contents are imported as a value.
+func IsDataFile(p string) bool {
+	switch path.Ext(p) {
+	case consts.JSONFileExtension, consts.TextFileExtension:
+		return true
+	}
+
+	return false
 }
@@ -0,0 +1,63 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// Interpolation represents an interpolated string literal.
+type Interpolation struct {
+	strings         []string
+	expressions     []interface{}
+	expressionInfos []*debug.Info
+	info            *debug.Info
+}
+
+// NewInterpolation creates an interpolated string literal from quoted string
+// literals, expressions between them and debug information of the expressions.
+func NewInterpolation(ss []string, es []interface{}, is []*debug.Info, i *debug.Info) Interpolation {
+	if len(ss) != len(es)+1 {
+		panic("A number of strings in an interpolation must be one more than expressions.")
+	} else if len(is) != len(es) {
+		panic("Every expression in an interpolation must have debug information.")
+	}
+
+	return Interpolation{ss, es, is, i}
+}
+
+// Strings returns quoted string literals in an interpolation.
+func (i Interpolation) Strings() []string {
+	return i.strings
+}
+
+// Expressions returns expressions embedded in an interpolation.
+func (i Interpolation) Expressions() []interface{} {
+	return i.expressions
+}
+
+// ExpressionInfos returns debug information of expressions embedded in an
+// interpolation.
+func (i Interpolation) ExpressionInfos() []*debug.Info {
+	return i.expressionInfos
+}
+
+// DebugInfo returns debug information of an interpolation.
+func (i Interpolation) DebugInfo() *debug.Info {
+	return i.info
+}
+
+func (i Interpolation) String() string {
+	ss := make([]string, 0, 2*len(i.strings))
+
+	for k, s := range i.strings {
+		ss = append(ss, s)
+
+		if k < len(i.expressions) {
+			ss = append(ss, fmt.Sprint(i.expressions[k]))
+		}
+	}
+
+	return "$" + strings.Join(ss, " ")
+}
@@ -1,16 +1,26 @@
 package ast
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
 
 // LetVar represents a let-variable statement node in ASTs.
 type LetVar struct {
 	name string
 	expr interface{}
+	info *debug.Info
 }
 
 // NewLetVar creates a LetVar from a variable name and its value of an expression.
 func NewLetVar(name string, expr interface{}) LetVar {
-	return LetVar{name, expr}
+	return LetVar{name, expr, nil}
+}
+
+// NewLetVarWithInfo creates a LetVar with debug information of its location.
+func NewLetVarWithInfo(name string, expr interface{}, i *debug.Info) LetVar {
+	return LetVar{name, expr, i}
 }
 
 // Name returns a variable name defined by the let-variable statement.
@@ -23,6 +33,11 @@ func (v LetVar) Expr() interface{} {
 	return v.expr
 }
 
+// DebugInfo returns debug information of the let-variable statement.
+func (v LetVar) DebugInfo() *debug.Info {
+	return v.info
+}
+
 func (v LetVar) String() string {
 	return fmt.Sprintf("(let %v %v)\n", v.name, v.expr)
 }
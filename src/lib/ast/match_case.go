@@ -5,12 +5,19 @@ import "fmt"
 // MatchCase represents a case of a pattern and corrensponding value.
 type MatchCase struct {
 	pattern interface{}
+	guard   interface{}
 	value   interface{}
 }
 
 // NewMatchCase creates a case in a match expression.
 func NewMatchCase(p interface{}, v interface{}) MatchCase {
-	return MatchCase{p, v}
+	return MatchCase{p, nil, v}
+}
+
+// NewGuardedMatchCase creates a case with a guard in a match expression. A nil
+// guard means that the case has no guard.
+func NewGuardedMatchCase(p, g, v interface{}) MatchCase {
+	return MatchCase{p, g, v}
 }
 
 // Pattern returns a pattern of a case in a match expression.
@@ -18,11 +25,21 @@ func (c MatchCase) Pattern() interface{} {
 	return c.pattern
 }
 
+// Guard returns a condition which must be true for a case to match or nil if
+// it has no guard.
+func (c MatchCase) Guard() interface{} {
+	return c.guard
+}
+
 // Value returns a value corrensponding to a pattern in a match expression.
 func (c MatchCase) Value() interface{} {
 	return c.value
 }
 
 func (c MatchCase) String() string {
+	if c.guard != nil {
+		return fmt.Sprintf("%v | %v %v", c.pattern, c.guard, c.value)
+	}
+
 	return fmt.Sprintf("%v %v", c.pattern, c.value)
 }
@@ -13,16 +13,32 @@ func NewOptionalParameter(n string, v interface{}) OptionalParameter {
 	return OptionalParameter{n, v}
 }
 
+// NewRequiredParameter creates a keyword parameter without a default value
+// whose argument must be passed by callers.
+func NewRequiredParameter(n string) OptionalParameter {
+	return OptionalParameter{n, nil}
+}
+
 // Name returns a name of an optional argument.
 func (o OptionalParameter) Name() string {
 	return o.name
 }
 
-// DefaultValue returns a default value of an optional argument.
+// DefaultValue returns a default value of an optional argument. It returns
+// nil if the argument is required.
 func (o OptionalParameter) DefaultValue() interface{} {
 	return o.defaultValue
 }
 
+// Required returns true if an argument must be passed by callers.
+func (o OptionalParameter) Required() bool {
+	return o.defaultValue == nil
+}
+
 func (o OptionalParameter) String() string {
+	if o.Required() {
+		return "!" + o.name
+	}
+
 	return fmt.Sprintf("%v %v", o.name, o.defaultValue)
 }
@@ -1,9 +1,13 @@
 package ast
 
+import "github.com/cloe-lang/cloe/src/lib/types"
+
 // Signature represents a signature of a function.
 type Signature struct {
 	positionals positionalParameters
 	keywords    keywordParameters
+	types       map[string]types.Type
+	result      types.Type
 }
 
 // NewSignature creates a Signature from {positional, keyword} x
@@ -36,6 +40,29 @@ func (s Signature) RestKeywords() string {
 	return s.keywords.rest
 }
 
+// WithTypes returns a signature with type annotations of parameters and a
+// result. Types of unannotated parameters and results are nil.
+func (s Signature) WithTypes(ts map[string]types.Type, r types.Type) Signature {
+	s.types = ts
+	s.result = r
+	return s
+}
+
+// Type returns a type annotation of a parameter or nil if it is not annotated.
+func (s Signature) Type(n string) types.Type {
+	return s.types[n]
+}
+
+// Types returns type annotations of parameters.
+func (s Signature) Types() map[string]types.Type {
+	return s.types
+}
+
+// Result returns a type annotation of results or nil if it is not annotated.
+func (s Signature) Result() types.Type {
+	return s.result
+}
+
 // NameToIndex converts an argument name into an index in arguments inside a signature.
 func (s Signature) NameToIndex() map[string]int {
 	m := map[string]int{}
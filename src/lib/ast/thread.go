@@ -0,0 +1,62 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// Thread represents a threading form which passes a value through
+// applications of functions one by one.
+type Thread struct {
+	last  bool
+	value interface{}
+	steps []App
+	info  *debug.Info
+}
+
+// NewThread creates a threading form. If last is true, a value is inserted
+// as the last positional argument of each step. Otherwise, it is inserted as
+// the first one.
+func NewThread(last bool, v interface{}, ss []App, i *debug.Info) Thread {
+	return Thread{last, v, ss, i}
+}
+
+// Last returns true if a value is inserted as the last positional argument.
+func (t Thread) Last() bool {
+	return t.last
+}
+
+// Value returns an initial value of a threading form.
+func (t Thread) Value() interface{} {
+	return t.value
+}
+
+// Steps returns applications in a threading form.
+func (t Thread) Steps() []App {
+	return t.steps
+}
+
+// DebugInfo returns debug information of a threading form.
+func (t Thread) DebugInfo() *debug.Info {
+	return t.info
+}
+
+func (t Thread) String() string {
+	ss := make([]string, 0, len(t.steps)+2)
+
+	if t.last {
+		ss = append(ss, "->>")
+	} else {
+		ss = append(ss, "->")
+	}
+
+	ss = append(ss, fmt.Sprint(t.value))
+
+	for _, s := range t.steps {
+		ss = append(ss, s.String())
+	}
+
+	return "(" + strings.Join(ss, " ") + ")"
+}
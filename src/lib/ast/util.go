@@ -38,6 +38,14 @@ func Convert(f func(interface{}) interface{}, x interface{}) interface{} {
 		return NewArguments(ps, ks)
 	case Import:
 		return x
+	case Interpolation:
+		es := make([]interface{}, 0, len(x.Expressions()))
+
+		for _, e := range x.Expressions() {
+			es = append(es, convert(e))
+		}
+
+		return NewInterpolation(x.Strings(), es, x.ExpressionInfos(), x.DebugInfo())
 	case KeywordArgument:
 		return NewKeywordArgument(x.Name(), convert(x.Value()))
 	case DefFunction:
@@ -53,8 +61,16 @@ func Convert(f func(interface{}) interface{}, x interface{}) interface{} {
 			ls,
 			convert(x.Body()),
 			x.DebugInfo())
+	case DefMacro:
+		return NewDefMacro(convert(x.Function()).(DefFunction))
+	case DefType:
+		return x
+	case Export:
+		return x
 	case LetVar:
-		return NewLetVar(x.Name(), convert(x.Expr()))
+		return NewLetVarWithInfo(x.Name(), convert(x.Expr()), x.DebugInfo())
+	case LetMatch:
+		return NewLetMatch(x.Pattern(), convert(x.Expr()))
 	case Match:
 		cs := make([]MatchCase, 0, len(x.Cases()))
 
@@ -64,7 +80,13 @@ func Convert(f func(interface{}) interface{}, x interface{}) interface{} {
 
 		return NewMatch(convert(x.Value()), cs)
 	case MatchCase:
-		return NewMatchCase(x.Pattern(), convert(x.Value()))
+		g := x.Guard()
+
+		if g != nil {
+			g = convert(g)
+		}
+
+		return NewGuardedMatchCase(x.Pattern(), g, convert(x.Value()))
 	case MutualRecursion:
 		fs := make([]DefFunction, 0, len(x.DefFunctions()))
 
@@ -74,9 +96,13 @@ func Convert(f func(interface{}) interface{}, x interface{}) interface{} {
 
 		return NewMutualRecursion(fs, x.DebugInfo())
 	case OptionalParameter:
+		if x.Required() {
+			return x
+		}
+
 		return NewOptionalParameter(x.Name(), convert(x.DefaultValue()))
 	case Effect:
-		return NewEffect(convert(x.Expr()), x.Expanded())
+		return NewEffect(convert(x.Expr()), x.Expanded(), x.DebugInfo())
 	case PositionalArgument:
 		return NewPositionalArgument(convert(x.Value()), x.Expanded())
 	case Signature:
@@ -86,7 +112,8 @@ func Convert(f func(interface{}) interface{}, x interface{}) interface{} {
 			ks = append(ks, convert(k).(OptionalParameter))
 		}
 
-		return NewSignature(x.Positionals(), x.RestPositionals(), ks, x.RestKeywords())
+		return NewSignature(x.Positionals(), x.RestPositionals(), ks, x.RestKeywords()).
+			WithTypes(x.Types(), x.Result())
 	case Switch:
 		cs := make([]SwitchCase, 0, len(x.Cases()))
 
@@ -97,6 +124,14 @@ func Convert(f func(interface{}) interface{}, x interface{}) interface{} {
 		return NewSwitch(convert(x.Value()), cs, convert(x.DefaultCase()))
 	case SwitchCase:
 		return NewSwitchCase(x.Pattern(), convert(x.Value()))
+	case Thread:
+		ss := make([]App, 0, len(x.Steps()))
+
+		for _, s := range x.Steps() {
+			ss = append(ss, convert(s).(App))
+		}
+
+		return NewThread(x.Last(), convert(x.Value()), ss, x.DebugInfo())
 	}
 
 	panic(fmt.Errorf("Invalid value: %#v", x))
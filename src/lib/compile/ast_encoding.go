@@ -0,0 +1,279 @@
+package compile
+
+import (
+	"encoding/gob"
+	"fmt"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// Encoded types mirror desugared AST nodes with exported fields so that they
+// can be serialized by encoding/gob.
+
+type encodedInfo struct {
+	File         string
+	LineNumber   int
+	LinePosition int
+	Source       string
+}
+
+type encodedLetVar struct {
+	Name string
+	Expr interface{}
+	Info *encodedInfo
+}
+
+type encodedDefFunction struct {
+	Name      string
+	Signature encodedSignature
+	Lets      []interface{}
+	Body      interface{}
+	Info      *encodedInfo
+}
+
+type encodedSignature struct {
+	Positionals     []string
+	RestPositionals string
+	Keywords        []encodedOptionalParameter
+	RestKeywords    string
+}
+
+type encodedOptionalParameter struct {
+	Name         string
+	DefaultValue interface{}
+}
+
+type encodedEffect struct {
+	Expr     interface{}
+	Expanded bool
+	Info     *encodedInfo
+}
+
+type encodedImport struct {
+	Path   string
+	Prefix string
+	Names  []encodedImportedName
+	Info   *encodedInfo
+}
+
+type encodedImportedName struct {
+	Name  string
+	Alias string
+}
+
+type encodedExport struct {
+	Names []string
+	Info  *encodedInfo
+}
+
+type encodedApp struct {
+	Function    interface{}
+	Positionals []encodedPositionalArgument
+	Keywords    []encodedKeywordArgument
+	Info        *encodedInfo
+}
+
+type encodedPositionalArgument struct {
+	Value    interface{}
+	Expanded bool
+}
+
+type encodedKeywordArgument struct {
+	Name  string
+	Value interface{}
+}
+
+type encodedSwitch struct {
+	Value       interface{}
+	Cases       []encodedSwitchCase
+	DefaultCase interface{}
+}
+
+type encodedSwitchCase struct {
+	Pattern string
+	Value   interface{}
+}
+
+func init() {
+	for _, x := range []interface{}{
+		encodedLetVar{},
+		encodedDefFunction{},
+		encodedEffect{},
+		encodedImport{},
+		encodedExport{},
+		encodedApp{},
+		encodedSwitch{},
+	} {
+		gob.Register(x)
+	}
+}
+
+// encodeNodes converts desugared AST nodes into encoded ones.
+func encodeNodes(xs []interface{}) []interface{} {
+	ys := make([]interface{}, 0, len(xs))
+
+	for _, x := range xs {
+		ys = append(ys, encodeNode(x))
+	}
+
+	return ys
+}
+
+func encodeNode(x interface{}) interface{} {
+	switch x := x.(type) {
+	case nil:
+		return nil
+	case string:
+		return x
+	case ast.LetVar:
+		return encodedLetVar{x.Name(), encodeNode(x.Expr()), encodeInfo(x.DebugInfo())}
+	case ast.DefFunction:
+		s := x.Signature()
+		ks := make([]encodedOptionalParameter, 0, len(s.Keywords()))
+
+		for _, k := range s.Keywords() {
+			ks = append(ks, encodedOptionalParameter{k.Name(), encodeNode(k.DefaultValue())})
+		}
+
+		return encodedDefFunction{
+			x.Name(),
+			encodedSignature{s.Positionals(), s.RestPositionals(), ks, s.RestKeywords()},
+			encodeNodes(x.Lets()),
+			encodeNode(x.Body()),
+			encodeInfo(x.DebugInfo()),
+		}
+	case ast.Effect:
+		return encodedEffect{encodeNode(x.Expr()), x.Expanded(), encodeInfo(x.DebugInfo())}
+	case ast.Import:
+		var ns []encodedImportedName
+
+		if x.Names() != nil {
+			ns = make([]encodedImportedName, 0, len(x.Names()))
+
+			for _, n := range x.Names() {
+				ns = append(ns, encodedImportedName{n.Name(), n.Alias()})
+			}
+		}
+
+		return encodedImport{x.Path(), x.Prefix(), ns, encodeInfo(x.DebugInfo())}
+	case ast.Export:
+		return encodedExport{x.Names(), encodeInfo(x.DebugInfo())}
+	case ast.App:
+		args := x.Arguments()
+		ps := make([]encodedPositionalArgument, 0, len(args.Positionals()))
+
+		for _, p := range args.Positionals() {
+			ps = append(ps, encodedPositionalArgument{encodeNode(p.Value()), p.Expanded()})
+		}
+
+		ks := make([]encodedKeywordArgument, 0, len(args.Keywords()))
+
+		for _, k := range args.Keywords() {
+			ks = append(ks, encodedKeywordArgument{k.Name(), encodeNode(k.Value())})
+		}
+
+		return encodedApp{encodeNode(x.Function()), ps, ks, encodeInfo(x.DebugInfo())}
+	case ast.Switch:
+		cs := make([]encodedSwitchCase, 0, len(x.Cases()))
+
+		for _, c := range x.Cases() {
+			cs = append(cs, encodedSwitchCase{c.Pattern(), encodeNode(c.Value())})
+		}
+
+		return encodedSwitch{encodeNode(x.Value()), cs, encodeNode(x.DefaultCase())}
+	}
+
+	panic(fmt.Errorf("Invalid type: %#v", x))
+}
+
+func encodeInfo(i *debug.Info) *encodedInfo {
+	if i == nil {
+		return nil
+	}
+
+	return &encodedInfo{i.File(), i.LineNumber(), i.LinePosition(), i.Source()}
+}
+
+// decodeNodes converts encoded nodes into desugared AST ones.
+func decodeNodes(xs []interface{}) []interface{} {
+	ys := make([]interface{}, 0, len(xs))
+
+	for _, x := range xs {
+		ys = append(ys, decodeNode(x))
+	}
+
+	return ys
+}
+
+func decodeNode(x interface{}) interface{} {
+	switch x := x.(type) {
+	case nil:
+		return nil
+	case string:
+		return x
+	case encodedLetVar:
+		return ast.NewLetVarWithInfo(x.Name, decodeNode(x.Expr), decodeInfo(x.Info))
+	case encodedDefFunction:
+		ks := make([]ast.OptionalParameter, 0, len(x.Signature.Keywords))
+
+		for _, k := range x.Signature.Keywords {
+			ks = append(ks, ast.NewOptionalParameter(k.Name, decodeNode(k.DefaultValue)))
+		}
+
+		return ast.NewDefFunction(
+			x.Name,
+			ast.NewSignature(x.Signature.Positionals, x.Signature.RestPositionals, ks, x.Signature.RestKeywords),
+			decodeNodes(x.Lets),
+			decodeNode(x.Body),
+			decodeInfo(x.Info))
+	case encodedEffect:
+		return ast.NewEffect(decodeNode(x.Expr), x.Expanded, decodeInfo(x.Info))
+	case encodedImport:
+		var ns []ast.ImportedName
+
+		if x.Names != nil {
+			ns = make([]ast.ImportedName, 0, len(x.Names))
+
+			for _, n := range x.Names {
+				ns = append(ns, ast.NewImportedName(n.Name, n.Alias))
+			}
+		}
+
+		return ast.NewImport(x.Path, x.Prefix, ns, decodeInfo(x.Info))
+	case encodedExport:
+		return ast.NewExport(x.Names, decodeInfo(x.Info))
+	case encodedApp:
+		ps := make([]ast.PositionalArgument, 0, len(x.Positionals))
+
+		for _, p := range x.Positionals {
+			ps = append(ps, ast.NewPositionalArgument(decodeNode(p.Value), p.Expanded))
+		}
+
+		ks := make([]ast.KeywordArgument, 0, len(x.Keywords))
+
+		for _, k := range x.Keywords {
+			ks = append(ks, ast.NewKeywordArgument(k.Name, decodeNode(k.Value)))
+		}
+
+		return ast.NewApp(decodeNode(x.Function), ast.NewArguments(ps, ks), decodeInfo(x.Info))
+	case encodedSwitch:
+		cs := make([]ast.SwitchCase, 0, len(x.Cases))
+
+		for _, c := range x.Cases {
+			cs = append(cs, ast.NewSwitchCase(c.Pattern, decodeNode(c.Value)))
+		}
+
+		return ast.NewSwitch(decodeNode(x.Value), cs, decodeNode(x.DefaultCase))
+	}
+
+	panic(fmt.Errorf("Invalid type: %#v", x))
+}
+
+func decodeInfo(i *encodedInfo) *debug.Info {
+	if i == nil {
+		return nil
+	}
+
+	return debug.NewInfo(i.File, i.LineNumber, i.LinePosition, i.Source)
+}
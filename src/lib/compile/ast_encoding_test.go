@@ -0,0 +1,56 @@
+package compile
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/desugar"
+	"github.com/cloe-lang/cloe/src/lib/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeNodes(t *testing.T) {
+	m, err := parse.MainModule("foo.cloe", `
+		(import "re")
+		(export x f)
+		(let x 42)
+		(def (f x ..xs . y 1 ..ys)
+			(let z (+ x y))
+			(match xs
+				[] z
+				[x ..xs] (f x ..xs . y z ..ys)))
+		(def (g x . !timeout) (+ x timeout))
+		(print (f x) (g 1 . timeout 2))
+		..[(print (f 1 2 . y 3))]
+	`)
+	assert.Nil(t, err)
+
+	m = desugar.Desugar(m)
+
+	b := &bytes.Buffer{}
+	assert.Nil(t, gob.NewEncoder(b).Encode(encodeNodes(m)))
+
+	n := []interface{}{}
+	assert.Nil(t, gob.NewDecoder(b).Decode(&n))
+
+	assert.Equal(t, fmt.Sprint(m), fmt.Sprint(decodeNodes(n)))
+	assert.Equal(t, encodeNodes(m), encodeNodes(decodeNodes(n)))
+}
+
+func TestEncodeNodeWithInvalidType(t *testing.T) {
+	defer func() {
+		assert.NotNil(t, recover())
+	}()
+
+	encodeNode(42)
+}
+
+func TestDecodeNodeWithInvalidType(t *testing.T) {
+	defer func() {
+		assert.NotNil(t, recover())
+	}()
+
+	decodeNode(42)
+}
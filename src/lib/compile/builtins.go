@@ -1,6 +1,9 @@
 package compile
 
 import (
+	"sort"
+	"sync"
+
 	"github.com/cloe-lang/cloe/src/lib/builtins"
 	"github.com/cloe-lang/cloe/src/lib/core"
 	"github.com/cloe-lang/cloe/src/lib/desugar"
@@ -10,6 +13,128 @@ import (
 
 const builtinsFilename = "<builtins>"
 
+const matchErrorMessage = "a value didn't match with any pattern"
+
+// internalBuiltinsSource is source code of built-in functions used only by
+// desugarers.
+const internalBuiltinsSource = `
+	(def (list ..args)
+		args)
+
+	(def (dictionary ..args)
+		(if (= args [])
+			{}
+			(insert
+				(dictionary ..(rest (rest args)))
+				(first args)
+				(first (rest args)))))
+`
+
+// builtinsSource is source code of built-in functions written in the language.
+const builtinsSource = `
+	(def (boolean? x) (= (typeOf x) "boolean"))
+	(def (dictionary? x) (= (typeOf x) "dictionary"))
+	(def (function? x) (= (typeOf x) "function"))
+	(def (list? x) (= (typeOf x) "list"))
+	(def (nil? x) (= (typeOf x) "nil"))
+	(def (number? x) (= (typeOf x) "number"))
+	(def (string? x) (= (typeOf x) "string"))
+
+	(def (index list elem . i 1)
+		(match list
+			[] (error "ElementNotFoundError" "Could not find an element in a list")
+			[first ..rest] (if (= first elem) i (index rest elem . i (+ i 1)))))
+
+	(def (map func list)
+		(match list
+			[] []
+			[first ..rest] [(func first) ..(map func rest)]))
+
+	(def (reduce func list)
+		(match list
+			[x] x
+			[x y ..xs] (reduce func [(func x y) ..xs])))
+
+	(def (generateMaxOrMinFunction name compare)
+		(def (maxOrMin ..args)
+			(match args
+				[]
+					(error
+						"ValueError"
+						(merge "Number of arguments to " name " function must be greater than 1."))
+				[x] x
+				[x y ..xs]
+					(match (if (compare x y) x y)
+						m (seq m (maxOrMin m ..xs)))))
+		maxOrMin)
+
+	(let max (generateMaxOrMinFunction "max" >))
+	(let min (generateMaxOrMinFunction "min" <))
+
+	(def (slice list . start 1 end nil)
+		(let end (if (= end nil) (max start (size list)) end))
+		(if
+			(string? list) (merge "" ..(slice (toList list) . start start end end))
+			(< end start) (error "ValueError" "start index must be less than end index")
+			(match list
+				[] []
+				[x ..xs]
+					(if
+						(= end 1) [x]
+						(> start 1) (slice xs . start (- start 1) end (- end 1))
+						(= start 1) [x ..(slice xs . start 1 end (- end 1))]
+						[]))))
+
+	(def (generateAndOrOrFunction name operator)
+		(def (f ..bs)
+			(match bs
+				[] (error
+					"ValueError"
+					(merge "Number of arguments to " name " function must be greater than 1"))
+				[x] x
+				[x y ..xs] (f (operator x y) ..xs)))
+		f)
+
+	(let and (generateAndOrOrFunction "and" (\ (x y) (if x y false))))
+	(let or (generateAndOrOrFunction "or" (\ (x y) (if x true y))))
+
+	(def (not bool)
+		(if bool false true))
+
+	(def (zip ..lists)
+		(if (or ..(map (\ (list) (= list [])) lists))
+			[]
+			[(map first lists) ..(zip ..(map rest lists))]))
+
+	(def (filter func list)
+		(match list
+			[] []
+			[x ..xs]
+				(match (filter func xs)
+					xs (if (func x) [x ..xs] xs))))
+
+	(def (sort list . less <)
+		(let sort (partial sort . less less))
+		(let pivot (@ list (// (size list) 2)))
+		(match list
+			[] []
+			[x] [x]
+			_ [
+				..(sort (filter (\ (x) (less x pivot)) list))
+				..(filter (\ (x) (and (not (less x pivot)) (not (less pivot x)))) list)
+				..(sort (filter (\ (x) (less pivot x)) list))]))
+
+	(let sortImpl sort)
+
+	(def (sort list . less <)
+		(let x (first list))
+		(match list
+			[] []
+			_ (if (less x x)
+				(error "ValueError" "less function should not be reflexive.")
+				(sortImpl list . less less))))
+`
+
 var goBuiltins = func() environment {
 	e := newEnvironment(scalar.Convert)
 
@@ -61,15 +186,20 @@ var goBuiltins = func() environment {
 		"catch": core.Catch,
 
 		"pure": core.Pure,
+
+		"implement": core.Implement,
 	} {
 		e.set(s, t)
 		e.set("$"+s, t)
 	}
 
 	for s, t := range map[string]core.Value{
-		"matchError": core.NewError("MatchError", "a value didn't match with any pattern"),
-		"y":          builtins.Y,
-		"ys":         builtins.Ys,
+		"constructor": core.Constructor,
+		"fields":      core.Fields,
+		"instance?":   core.IsInstance,
+		"matchError":  core.NewError("MatchError", matchErrorMessage),
+		"y":           builtins.Y,
+		"ys":          builtins.Ys,
 	} {
 		e.set("$"+s, t)
 	}
@@ -77,127 +207,26 @@ var goBuiltins = func() environment {
 	return e
 }()
 
+var (
+	builtinsCache environment
+	builtinsOnce  sync.Once
+)
+
+// builtinsEnvironment returns a copy of an environment of built-in functions.
+// The functions written in the language are compiled only once.
 func builtinsEnvironment() environment {
+	builtinsOnce.Do(func() { builtinsCache = compileBuiltins() })
+	return builtinsCache.copy()
+}
+
+func compileBuiltins() environment {
 	e := goBuiltins.copy()
 
-	for n, t := range compileBuiltinModule(e.copy(), builtinsFilename, `
-			(def (list ..args)
-				args)
-
-			(def (dictionary ..args)
-				(if (= args [])
-					{}
-					(insert
-						(dictionary ..(rest (rest args)))
-						(first args)
-						(first (rest args)))))
-		`) {
+	for n, t := range compileBuiltinModule(e.copy(), builtinsFilename, internalBuiltinsSource) {
 		e.set("$"+n, t)
 	}
 
-	for n, t := range compileBuiltinModule(e.copy(), builtinsFilename, `
-			(def (boolean? x) (= (typeOf x) "boolean"))
-			(def (dictionary? x) (= (typeOf x) "dictionary"))
-			(def (function? x) (= (typeOf x) "function"))
-			(def (list? x) (= (typeOf x) "list"))
-			(def (nil? x) (= (typeOf x) "nil"))
-			(def (number? x) (= (typeOf x) "number"))
-			(def (string? x) (= (typeOf x) "string"))
-
-			(def (index list elem . i 1)
-				(match list
-					[] (error "ElementNotFoundError" "Could not find an element in a list")
-					[first ..rest] (if (= first elem) i (index rest elem . i (+ i 1)))))
-
-			(def (map func list)
-				(match list
-					[] []
-					[first ..rest] [(func first) ..(map func rest)]))
-
-			(def (reduce func list)
-				(match list
-					[x] x
-					[x y ..xs] (reduce func [(func x y) ..xs])))
-
-			(def (generateMaxOrMinFunction name compare)
-				(def (maxOrMin ..args)
-					(match args
-						[]
-							(error
-								"ValueError"
-								(merge "Number of arguments to " name " function must be greater than 1."))
-						[x] x
-						[x y ..xs]
-							(match (if (compare x y) x y)
-								m (seq m (maxOrMin m ..xs)))))
-				maxOrMin)
-
-			(let max (generateMaxOrMinFunction "max" >))
-			(let min (generateMaxOrMinFunction "min" <))
-
-			(def (slice list . start 1 end nil)
-				(let end (if (= end nil) (max start (size list)) end))
-				(if
-					(string? list) (merge "" ..(slice (toList list) . start start end end))
-					(< end start) (error "ValueError" "start index must be less than end index")
-					(match list
-						[] []
-						[x ..xs]
-							(if
-								(= end 1) [x]
-								(> start 1) (slice xs . start (- start 1) end (- end 1))
-								(= start 1) [x ..(slice xs . start 1 end (- end 1))]
-								[]))))
-
-			(def (generateAndOrOrFunction name operator)
-				(def (f ..bs)
-					(match bs
-						[] (error
-							"ValueError"
-							(merge "Number of arguments to " name " function must be greater than 1"))
-						[x] x
-						[x y ..xs] (f (operator x y) ..xs)))
-				f)
-
-			(let and (generateAndOrOrFunction "and" (\ (x y) (if x y false))))
-			(let or (generateAndOrOrFunction "or" (\ (x y) (if x true y))))
-
-			(def (not bool)
-				(if bool false true))
-
-			(def (zip ..lists)
-				(if (or ..(map (\ (list) (= list [])) lists))
-					[]
-					[(map first lists) ..(zip ..(map rest lists))]))
-
-			(def (filter func list)
-				(match list
-					[] []
-					[x ..xs]
-						(match (filter func xs)
-							xs (if (func x) [x ..xs] xs))))
-
-			(def (sort list . less <)
-				(let sort (partial sort . less less))
-				(let pivot (@ list (// (size list) 2)))
-				(match list
-					[] []
-					[x] [x]
-					_ [
-						..(sort (filter (\ (x) (less x pivot)) list))
-						..(filter (\ (x) (and (not (less x pivot)) (not (less pivot x)))) list)
-						..(sort (filter (\ (x) (less pivot x)) list))]))
-
-			(let sortImpl sort)
-
-			(def (sort list . less <)
-				(let x (first list))
-				(match list
-					[] []
-					_ (if (less x x)
-						(error "ValueError" "less function should not be reflexive.")
-						(sortImpl list . less less))))
-		`) {
+	for n, t := range compileBuiltinModule(e.copy(), builtinsFilename, builtinsSource) {
 		e.set(n, t)
 		e.set("$"+n, t)
 	}
@@ -205,6 +234,22 @@ func builtinsEnvironment() environment {
 	return e
 }
 
+// BuiltinNames returns sorted names of built-in functions and values available
+// in every module.
+func BuiltinNames() []string {
+	ss := []string{"true", "false", "nil"}
+
+	for s := range builtinsEnvironment().toMap() {
+		if s[:1] != "$" {
+			ss = append(ss, s)
+		}
+	}
+
+	sort.Strings(ss)
+
+	return ss
+}
+
 func compileBuiltinModule(e environment, path, source string) module {
 	m, err := parse.SubModule(path, source)
 
@@ -15,6 +15,18 @@ func TestBuiltinsEnvironment(t *testing.T) {
 	builtinsEnvironment()
 }
 
+func TestBuiltinNames(t *testing.T) {
+	ss := BuiltinNames()
+
+	for _, s := range []string{"+", "map", "nil", "print"} {
+		assert.Contains(t, ss, s)
+	}
+
+	for _, s := range ss {
+		assert.NotEqual(t, "$", s[:1])
+	}
+}
+
 func TestCompileBuiltinModule(t *testing.T) {
 	compileBuiltinModule(newEnvironment(testFallback), "", `(def (foo x) x)`)
 }
@@ -6,7 +6,6 @@ import (
 	"path"
 	"path/filepath"
 
-	"github.com/cloe-lang/cloe/src/lib/desugar"
 	"github.com/cloe-lang/cloe/src/lib/parse"
 )
 
@@ -18,14 +17,31 @@ func Compile(p string) ([]Effect, error) {
 		return nil, err
 	}
 
-	m, err := parse.MainModule(q, s)
+	return compileSource(q, s, filepath.ToSlash(path.Dir(p))) // path.Dir("") == "."
+}
+
+// CompileSource compiles source code of a main module at a path into effects
+// of thunks without reading the file.
+func CompileSource(p, s string) ([]Effect, error) {
+	return compileSource(p, s, filepath.ToSlash(path.Dir(p)))
+}
+
+func compileSource(p, s, d string) ([]Effect, error) {
+	m, err := parse.MainModule(p, s)
 
 	if err != nil {
 		return nil, err
 	}
 
 	c := newCompiler(builtinsEnvironment(), newModulesCache())
-	return c.compileModule(desugar.Desugar(m), filepath.ToSlash(path.Dir(p))) // path.Dir("") == "."
+	c.imports = newImportStack(p)
+	m, err = c.desugar(m, d)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.compileModule(m, d)
 }
 
 func readFileOrStdin(path string) (string, string, error) {
@@ -5,9 +5,11 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"testing"
 
 	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/debug"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,6 +47,60 @@ func TestCompileSourceOfInvalidSyntax(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestCompileWithUnknownNames(t *testing.T) {
+	f, err := ioutil.TempFile("", "")
+	assert.Nil(t, err)
+
+	f.WriteString("(def (f x) (g x))\n(print (h 42))")
+
+	err = f.Close()
+	assert.Nil(t, err)
+
+	_, err = Compile(f.Name())
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(es))
+
+	for i, e := range es {
+		assert.Equal(t, "NameError", e.(*debug.Error).Name())
+		assert.Equal(t, i+1, e.(*debug.Error).Info().LineNumber())
+	}
+}
+
+func TestCompileWithUnknownNamesInStatements(t *testing.T) {
+	f, err := ioutil.TempFile("", "")
+	assert.Nil(t, err)
+
+	f.WriteString("(let x y)\nz\n(print x)")
+
+	err = f.Close()
+	assert.Nil(t, err)
+
+	_, err = Compile(f.Name())
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(es))
+
+	for i, e := range es {
+		assert.Equal(t, "NameError", e.(*debug.Error).Name())
+		assert.NotNil(t, e.(*debug.Error).Info())
+		assert.Equal(t, i+1, e.(*debug.Error).Info().LineNumber())
+	}
+}
+
+func TestCompileSource(t *testing.T) {
+	es, err := CompileSource("main.cloe", `(def (f x) x) (print (f 42))`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(es))
+
+	_, err = CompileSource("main.cloe", `(print (f 42))`)
+
+	assert.NotNil(t, err)
+}
+
 func TestCompileWithInvalidPath(t *testing.T) {
 	_, err := Compile("I'm the invalid path.")
 	assert.NotNil(t, err)
@@ -73,6 +129,158 @@ func TestCompileWithSubModule(t *testing.T) {
 	assert.Equal(t, 1, len(es))
 }
 
+func TestCompileWithPrivateNames(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	for n, s := range map[string]string{
+		"mod":  `(def (_twice x) (* 2 x)) (def (double x) (_twice x))`,
+		"mod2": `(export double) (def (twice x) (* 2 x)) (def (double x) (twice x))`,
+		"bad":  `(export foo bar) (let foo 42)`,
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(d, n+consts.FileExtension), []byte(s), 0600))
+	}
+
+	for _, c := range []struct {
+		source, name string
+	}{
+		{`(import "./mod") (import "./mod2") (print (mod.double 21) (mod2.double 21))`, ""},
+		{`(import "./mod") (print (mod._twice 21))`, "NameError"},
+		{`(import "./mod2") (print (mod2.twice 21))`, "NameError"},
+		{`(import "./bad")`, "ExportError"},
+	} {
+		_, err := CompileSource(filepath.Join(d, "main.cloe"), c.source)
+
+		if c.name == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.Contains(t, err.Error(), c.name+":")
+		}
+	}
+}
+
+func TestCompileWithSelectiveImports(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	assert.Nil(t, ioutil.WriteFile(
+		filepath.Join(d, "mod"+consts.FileExtension),
+		[]byte(`(def (_twice x) (* 2 x)) (def (double x) (_twice x)) (defmacro (id x) x)`),
+		0600))
+
+	for _, c := range []struct {
+		source, name string
+	}{
+		{`(import "./mod" (double)) (print (double 21))`, ""},
+		{`(import "./mod" (double . as twice id)) (print (id (twice 21)))`, ""},
+		{`(import "re" (match . as m)) (print (m "a" "a"))`, ""},
+		{`(import "./mod" (double)) (print (mod.double 21))`, "NameError"},
+		{`(import "./mod" (_twice))`, "ImportError"},
+		{`(import "./mod" (triple))`, "ImportError"},
+		{`(import "re" (foo))`, "ImportError"},
+	} {
+		_, err := CompileSource(filepath.Join(d, "main.cloe"), c.source)
+
+		if c.name == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.Contains(t, err.Error(), c.name+":")
+		}
+	}
+}
+
+func TestCompileWithFailedSelectiveImports(t *testing.T) {
+	_, err := CompileSource("main.cloe", `(import "./none" (foo)) (print (foo 42) (bar 42))`)
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(es))
+	assert.Equal(t, "ImportError", es[0].(*debug.Error).Name())
+	assert.Equal(t, "NameError", es[1].(*debug.Error).Name())
+}
+
+func TestCompileWithImportCycles(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	for n, s := range map[string]string{
+		"foo": `(import "./bar") (let x 42)`,
+		"bar": `(import "./baz") (let y 42)`,
+		"baz": `(import "./foo") (let z 42)`,
+		"qux": `(import "./qux")`,
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(d, n+consts.FileExtension), []byte(s), 0600))
+	}
+
+	for _, c := range []struct {
+		source, message string
+	}{
+		{`(import "./foo")`, "import cycle: foo.cloe -> bar.cloe -> baz.cloe -> foo.cloe"},
+		{`(import "./qux")`, "import cycle: qux.cloe -> qux.cloe"},
+		{`(import "./main") (print 42)`, "import cycle: main.cloe -> main.cloe"},
+	} {
+		_, err := CompileSource(filepath.Join(d, "main.cloe"), c.source)
+
+		es, ok := err.(debug.Errors)
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(es))
+		assert.Equal(t, c.message, es[0].(*debug.Error).Message())
+	}
+}
+
+func TestCompileWithDataFiles(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	for f, s := range map[string]string{
+		"config.json": `{"name": "foo", "values": [1, 2, 3]}`,
+		"banner.txt":  "Hello, world!\n",
+		"bad.json":    `{"name": }`,
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(d, f), []byte(s), 0600))
+	}
+
+	for _, c := range []struct {
+		source, name string
+	}{
+		{`(import "./config.json") (print (@ config "name"))`, ""},
+		{`(import "./banner.txt" . as banner) (print banner)`, ""},
+		{`(import c "./config.json") (import "./config.json") (print (= c config))`, ""},
+		{`(import "./bad.json")`, "ImportError"},
+		{`(import "./none.txt")`, "ImportError"},
+		{`(import "./none.txt") (print none)`, "ImportError"},
+	} {
+		_, err := CompileSource(filepath.Join(d, "main.cloe"), c.source)
+
+		if c.name == "" {
+			assert.Nil(t, err)
+		} else {
+			es, ok := err.(debug.Errors)
+			assert.True(t, ok)
+			assert.Equal(t, 1, len(es))
+			assert.Equal(t, c.name, es[0].(*debug.Error).Name())
+		}
+	}
+}
+
+func TestModuleFile(t *testing.T) {
+	m := createModuleScript(t)
+
+	f, err := ModuleFile(m, "")
+
+	assert.Nil(t, err)
+	assert.Equal(t, m+consts.FileExtension, f)
+
+	f, err = ModuleFile("./foo", "/bar")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "/bar/foo"+consts.FileExtension, f)
+}
+
 func createModuleScript(t *testing.T) string {
 	f, err := ioutil.TempFile("", "module")
 	assert.Nil(t, err)
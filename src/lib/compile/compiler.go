@@ -7,10 +7,12 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/cloe-lang/cloe/src/lib/ast"
 	"github.com/cloe-lang/cloe/src/lib/consts"
 	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
 	"github.com/cloe-lang/cloe/src/lib/desugar"
 	"github.com/cloe-lang/cloe/src/lib/ir"
 	"github.com/cloe-lang/cloe/src/lib/modules"
@@ -18,22 +20,33 @@ import (
 )
 
 type compiler struct {
-	env   environment
-	cache modulesCache
+	env           environment
+	cache         modulesCache
+	info          *debug.Info
+	macros        desugar.Macros
+	errors        []error
+	failedImports []string
+	definedMacros []string
+	privateNames  map[string]bool
+	imports       importStack
 }
 
 func newCompiler(e environment, c modulesCache) compiler {
-	return compiler{e, c}
+	return compiler{env: e, cache: c, macros: desugar.Macros{}, privateNames: map[string]bool{}}
 }
 
 func (c *compiler) compileModule(m []interface{}, d string) ([]Effect, error) {
 	es := []Effect{}
 
 	for _, s := range m {
+		c.info = nil
+
 		switch x := s.(type) {
 		case ast.LetVar:
+			c.info = x.DebugInfo()
 			c.env.set(x.Name(), c.exprToThunk(x.Expr()))
 		case ast.DefFunction:
+			c.info = x.DebugInfo()
 			sig := x.Signature()
 			ls := x.Lets()
 
@@ -54,66 +67,195 @@ func (c *compiler) compileModule(m []interface{}, d string) ([]Effect, error) {
 					vars,
 					c.exprToIR(varToIndex, x.Body())))
 		case ast.Effect:
-			es = append(es, NewEffect(c.exprToThunk(x.Expr()), x.Expanded()))
+			c.info = x.DebugInfo()
+			es = append(es, NewEffect(c.exprToThunk(x.Expr()), x.Expanded(), x.DebugInfo()))
 		case ast.Import:
 			if c.cache == nil {
 				return nil, errors.New("import statement is unavailable")
 			}
 
-			m, ok := modules.Modules[x.Path()]
-
-			if !ok {
-				var err error
-				m, err = c.importLocalModule(x.Path(), d)
+			m, err := c.importModule(x, d)
 
-				if err != nil {
-					return nil, err
-				}
+			if err == nil {
+				err = checkImportedNames(x, func(n string) bool {
+					_, ok := m[n]
+					_, isMacro := m[macroPrefix+n]
+					return ok || isMacro
+				})
 			}
 
-			p := x.Prefix()
-
-			if p != "" {
-				p += "."
+			if err != nil {
+				c.importError(x, err)
+				continue
 			}
 
-			for k, v := range m {
-				c.env.set(p+k, v)
-			}
+			c.importNames(x, m)
+		case ast.Export:
 		default:
 			panic(fmt.Errorf("Invalid type: %#v", x))
 		}
 	}
 
+	if len(c.errors) != 0 {
+		es := c.errors
+		c.errors = nil
+		return nil, debug.Errors(es)
+	}
+
 	return es, nil
 }
 
-func (c *compiler) compileSubModule(p string) (module, error) {
-	if i, err := os.Stat(p); err == nil && i.IsDir() {
-		p = path.Join(p, consts.ModuleFilename)
+func (c *compiler) importModule(i ast.Import, d string) (module, error) {
+	if c.cache == nil {
+		return nil, errors.New("import statement is unavailable")
+	} else if m, ok := modules.Modules[i.Path()]; ok {
+		return m, nil
+	}
+
+	return c.importLocalModule(i, d)
+}
+
+// importNames sets names and macros in an imported module.
+func (c *compiler) importNames(i ast.Import, m module) {
+	for k, v := range m {
+		if n := strings.TrimPrefix(k, privatePrefix); n != k {
+			if l, ok := i.LocalName(n); ok {
+				c.privateNames[l] = true
+			}
+		} else if n := strings.TrimPrefix(k, macroPrefix); n != k {
+			if l, ok := i.LocalName(n); ok {
+				c.macros[l] = v
+				c.env.set(macroPrefix+l, v)
+			}
+		} else if l, ok := i.LocalName(k); ok {
+			c.env.set(l, v)
+		}
+	}
+}
+
+func (c *compiler) importError(i ast.Import, err error) {
+	c.failedImports = append(c.failedImports, failedImportNames(i)...)
+
+	switch err := err.(type) {
+	case debug.Errors:
+		c.errors = append(c.errors, err...)
+	case *debug.Error:
+		c.errors = append(c.errors, err)
+	default:
+		c.errors = append(c.errors, debug.NewError(i.DebugInfo(), "ImportError", "%v", err))
+	}
+}
+
+// get resolves a name into a value. It records an error and returns a
+// placeholder value instead if the name is not found.
+func (c *compiler) get(s string) core.Value {
+	v, err := c.env.lookup(s)
+
+	if err == nil {
+		return v
+	}
+
+	if isFailedImportName(s, c.failedImports) {
+		return core.Nil
+	}
+
+	if c.privateNames[s] {
+		err = fmt.Errorf("the name, %s is private in its module", s)
 	}
 
+	c.errors = append(c.errors, debug.NewError(c.info, "NameError", "%v", err))
+
+	return core.Nil
+}
+
+func (c *compiler) compileSubModule(p string, is importStack) (module, error) {
+	p = modulePath(p)
 	bs, err := ioutil.ReadFile(filepath.FromSlash(p + consts.FileExtension))
 
 	if err != nil {
 		return nil, err
 	}
 
-	m, err := parse.SubModule(p, string(bs))
+	cc := newCompiler(builtinsEnvironment(), c.cache)
+	cc.imports = is
+	c = &cc
+	m, err := c.desugarSubModule(p, bs)
 
 	if err != nil {
 		return nil, err
 	}
 
-	cc := newCompiler(builtinsEnvironment(), c.cache)
-	c = &cc
-	_, err = c.compileModule(desugar.Desugar(m), path.Dir(p))
+	_, err = c.compileModule(m, path.Dir(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.exportedModule(m)
+}
+
+// exportedModule returns a module of names exported by a compiled module.
+// Names of private members are kept with a prefix.
+func (c *compiler) exportedModule(m []interface{}) (module, error) {
+	ns := definedNames(m)
+
+	if err := checkExports(m, append(ns, c.definedMacros...)); err != nil {
+		return nil, err
+	}
+
+	es := exports(m)
+	e := module{}
+
+	export := func(n, k string) {
+		if isExported(n, es) {
+			e[k] = c.env.get(k)
+		} else if !strings.HasPrefix(n, "$") {
+			e[privatePrefix+n] = core.Nil
+		}
+	}
+
+	for _, n := range ns {
+		export(n, n)
+	}
+
+	for _, n := range c.definedMacros {
+		export(n, macroPrefix+n)
+	}
+
+	return e, nil
+}
+
+// desugarSubModule parses and desugars a sub module using a disk cache.
+// Modules defining or using macros are not cached.
+func (c *compiler) desugarSubModule(p string, source []byte) ([]interface{}, error) {
+	d := newDiskCache()
+
+	if m, ok := d.Get(p, source); ok {
+		if err := c.compileMacros(m, path.Dir(p)); err != nil {
+			return nil, err
+		} else if !c.expandsMacros(m) {
+			return m, nil
+		}
+	}
+
+	a, err := parse.SubModule(p, string(source))
 
 	if err != nil {
 		return nil, err
 	}
 
-	return c.env.toMap(), nil
+	m, err := c.desugar(a, path.Dir(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.expandsMacros(a) {
+		// Failures of caching should not prevent compilation.
+		_ = d.Set(p, source, m)
+	}
+
+	return m, nil
 }
 
 func (c *compiler) exprToThunk(expr interface{}) core.Value {
@@ -134,7 +276,11 @@ func (c *compiler) compileOptionalParameters(os []ast.OptionalParameter) []core.
 	ps := make([]core.OptionalParameter, 0, len(os))
 
 	for _, o := range os {
-		ps = append(ps, core.NewOptionalParameter(o.Name(), c.exprToThunk(o.DefaultValue())))
+		if o.Required() {
+			ps = append(ps, core.NewRequiredParameter(o.Name()))
+		} else {
+			ps = append(ps, core.NewOptionalParameter(o.Name(), c.exprToThunk(o.DefaultValue())))
+		}
 	}
 
 	return ps
@@ -147,8 +293,13 @@ func (c *compiler) exprToIR(varToIndex map[string]int, expr interface{}) interfa
 			return i
 		}
 
-		return c.env.get(x)
+		return c.get(x)
 	case ast.App:
+		if i := x.DebugInfo(); i != nil {
+			defer func(i *debug.Info) { c.info = i }(c.info)
+			c.info = i
+		}
+
 		args := x.Arguments()
 
 		ps := make([]ir.PositionalArgument, 0, len(args.Positionals()))
@@ -170,7 +321,7 @@ func (c *compiler) exprToIR(varToIndex map[string]int, expr interface{}) interfa
 
 		for _, k := range x.Cases() {
 			cs = append(cs, ir.NewCase(
-				c.env.get(k.Pattern()),
+				c.get(k.Pattern()),
 				c.exprToIR(varToIndex, k.Value())))
 		}
 
@@ -186,32 +337,32 @@ func (c *compiler) exprToIR(varToIndex map[string]int, expr interface{}) interfa
 	panic(fmt.Errorf("Invalid type: %#v", expr))
 }
 
-func (c *compiler) importLocalModule(p, d string) (module, error) {
-	var err error
+func (c *compiler) importLocalModule(i ast.Import, d string) (module, error) {
+	p, err := resolveModulePath(i.Path(), d)
 
-	if p[0] == '.' && (p[:2] == "./" || p[:3] == "../") {
-		p, err = filepath.Abs(filepath.FromSlash(path.Join(d, p)))
+	if err != nil {
+		return nil, err
+	}
+
+	if m, ok := c.cache.Get(p); ok {
+		return m, nil
+	} else if i.Data() {
+		m, err := dataModule(p)
 
 		if err != nil {
 			return nil, err
 		}
-	} else if !path.IsAbs(p) {
-		q := os.Getenv(consts.PathName)
 
-		if q == "" {
-			return nil, fmt.Errorf("environment variable %s is not set", consts.PathName)
-		} else if !path.IsAbs(q) {
-			return nil, fmt.Errorf("%s is not absolute", consts.PathName)
-		}
-
-		p = path.Join(q, p)
+		return m, c.cache.Set(p, m)
 	}
 
-	if m, ok := c.cache.Get(p); ok {
-		return m, nil
+	is, err := c.imports.push(p, i.DebugInfo())
+
+	if err != nil {
+		return nil, err
 	}
 
-	m, err := c.compileSubModule(p)
+	m, err := c.compileSubModule(p, is)
 
 	if err != nil {
 		return nil, err
@@ -223,3 +374,51 @@ func (c *compiler) importLocalModule(p, d string) (module, error) {
 
 	return m, nil
 }
+
+// ModuleFile resolves a path of a local module imported in a directory into a
+// path of its source file.
+func ModuleFile(p, d string) (string, error) {
+	p, err := resolveModulePath(p, d)
+
+	if err != nil {
+		return "", err
+	} else if ast.IsDataFile(p) {
+		return filepath.FromSlash(p), nil
+	}
+
+	return filepath.FromSlash(modulePath(p) + consts.FileExtension), nil
+}
+
+func resolveModulePath(p, d string) (string, error) {
+	if p == "" {
+		return "", errors.New("module path is empty")
+	} else if p == "." || p == ".." || strings.HasPrefix(p, "./") || strings.HasPrefix(p, "../") {
+		return filepath.Abs(filepath.FromSlash(path.Join(d, p)))
+	} else if path.IsAbs(p) {
+		return p, nil
+	}
+
+	ds, err := searchPath(d)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, d := range ds {
+		if q := filepath.Join(d, filepath.FromSlash(p)); moduleExists(q) {
+			return q, nil
+		}
+	}
+
+	return "", fmt.Errorf("module %s is not found in %s", p, strings.Join(ds, ", "))
+}
+
+// modulePath converts a path of a module into a path of its source file with
+// no file extension.
+func modulePath(p string) string {
+	if i, err := os.Stat(p); err == nil && i.IsDir() {
+		return path.Join(p, consts.ModuleFilename)
+	}
+
+	return p
+}
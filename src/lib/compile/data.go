@@ -0,0 +1,82 @@
+package compile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/modules/json"
+	"github.com/cloe-lang/cloe/src/lib/types"
+)
+
+// readDataFile reads a data file of a path and returns its contents and a
+// value decoded from them.
+func readDataFile(p string) (string, core.Value, error) {
+	bs, err := ioutil.ReadFile(p)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	s := string(bs)
+
+	if path.Ext(p) != consts.JSONFileExtension {
+		return s, core.NewString(s), nil
+	}
+
+	v := core.EvalPure(json.DecodeString(s))
+
+	if e, ok := v.(*core.ErrorType); ok {
+		return "", nil, fmt.Errorf("failed to decode %s: %s", p, e.Message())
+	}
+
+	return s, v, nil
+}
+
+// dataModule creates a module of a data file.
+func dataModule(p string) (module, error) {
+	_, v, err := readDataFile(p)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return module{consts.Names.DataMember: v}, nil
+}
+
+// goDataModule creates a module of Go expressions of a data file.
+func (g *goGenerator) goDataModule(p string) (goModule, error) {
+	s, _, err := readDataFile(p)
+
+	if err != nil {
+		return nil, err
+	}
+
+	e := fmt.Sprintf("core.NewString(%q)", s)
+
+	if path.Ext(p) == consts.JSONFileExtension {
+		g.program.usesModules = true
+		e = fmt.Sprintf("core.PApp(modules.Modules[\"json\"][\"decode\"], %s)", e)
+	}
+
+	return goModule{consts.Names.DataMember: g.program.constant("d", e)}, nil
+}
+
+// dataModuleTypes returns types of a module of a data file.
+func dataModuleTypes(p string) (map[string]types.Type, error) {
+	_, v, err := readDataFile(p)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t := types.Any
+
+	if _, ok := v.(core.StringType); ok {
+		t = types.String
+	}
+
+	return map[string]types.Type{consts.Names.DataMember: t}, nil
+}
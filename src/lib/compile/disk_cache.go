@@ -0,0 +1,190 @@
+package compile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	godebug "runtime/debug"
+	"sync"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/modules"
+)
+
+// diskCache is a persistent cache of desugared modules stored in the language
+// directory. Entries are keyed by absolute paths of modules and invalidated
+// when hashes of their source, source of modules they import, or the compiler
+// change. Indirect dependencies of a module are validated by their own entries
+// when they are imported.
+type diskCache struct {
+	directory string // empty if the cache is disabled
+	compiler  []byte
+}
+
+type diskCacheEntry struct {
+	Hash    []byte
+	Imports []string
+	Module  []interface{}
+}
+
+var (
+	compilerHashCache []byte
+	compilerHashOnce  sync.Once
+)
+
+// newDiskCache creates a cache in the language directory. The cache is
+// disabled if the directory or an executable of the compiler is not found.
+func newDiskCache() diskCache {
+	d, err := LanguageDirectory()
+
+	if err != nil {
+		return diskCache{}
+	}
+
+	compilerHashOnce.Do(func() { compilerHashCache = compilerHash() })
+
+	if compilerHashCache == nil {
+		return diskCache{}
+	}
+
+	return diskCache{filepath.Join(d, consts.CacheDirectory), compilerHashCache}
+}
+
+// Get gets a desugared module of an absolute path if its source, modules it
+// imports, and the compiler are unchanged.
+func (c diskCache) Get(p string, source []byte) ([]interface{}, bool) {
+	if c.directory == "" {
+		return nil, false
+	}
+
+	bs, err := ioutil.ReadFile(c.filename(p))
+
+	if err != nil {
+		return nil, false
+	}
+
+	e := diskCacheEntry{}
+
+	if err := gob.NewDecoder(bytes.NewReader(bs)).Decode(&e); err != nil ||
+		!bytes.Equal(e.Hash, c.hash(p, source, e.Imports)) {
+		return nil, false
+	}
+
+	return decodeNodes(e.Module), true
+}
+
+// Set stores a desugared module of an absolute path with a hash of its source.
+func (c diskCache) Set(p string, source []byte, m []interface{}) error {
+	if c.directory == "" {
+		return nil
+	}
+
+	is := importPaths(m)
+	b := &bytes.Buffer{}
+
+	if err := gob.NewEncoder(b).Encode(diskCacheEntry{c.hash(p, source, is), is, encodeNodes(m)}); err != nil {
+		return err
+	} else if err := os.MkdirAll(c.directory, 0700); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(c.directory, "")
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(b.Bytes()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	} else if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), c.filename(p))
+}
+
+func (c diskCache) filename(p string) string {
+	h := sha256.Sum256([]byte(p))
+	return filepath.Join(c.directory, hex.EncodeToString(h[:]))
+}
+
+// hash returns a hash of source of a module of an absolute path, files of
+// modules it imports, and the compiler.
+func (c diskCache) hash(p string, source []byte, is []string) []byte {
+	h := sha256.New()
+	writeHashField(h, c.compiler)
+	writeHashField(h, source)
+
+	for _, i := range is {
+		f, err := ModuleFile(i, path.Dir(p))
+
+		if err != nil {
+			writeHashField(h, nil)
+			continue
+		}
+
+		bs, _ := ioutil.ReadFile(f)
+		writeHashField(h, []byte(f))
+		writeHashField(h, bs)
+	}
+
+	return h.Sum(nil)
+}
+
+func writeHashField(h hash.Hash, bs []byte) {
+	fmt.Fprintf(h, "%d:", len(bs))
+	h.Write(bs)
+}
+
+// importPaths returns paths of local modules imported by a module.
+func importPaths(m []interface{}) []string {
+	ss := []string{}
+
+	for _, s := range m {
+		if i, ok := s.(ast.Import); ok {
+			if _, ok := modules.Modules[i.Path()]; !ok {
+				ss = append(ss, i.Path())
+			}
+		}
+	}
+
+	return ss
+}
+
+// compilerHash returns a hash identifying the running compiler. Released
+// versions are identified by their build information and others by their
+// executables. It returns nil if the compiler cannot be identified.
+func compilerHash() []byte {
+	if i, ok := godebug.ReadBuildInfo(); ok && i.Main.Path != "" {
+		if _, d := goModuleVersion(i); d == "" {
+			h := sha256.Sum256([]byte(i.String()))
+			return h[:]
+		}
+	}
+
+	p, err := os.Executable()
+
+	if err != nil {
+		return nil
+	}
+
+	bs, err := ioutil.ReadFile(p)
+
+	if err != nil {
+		return nil
+	}
+
+	h := sha256.Sum256(bs)
+	return h[:]
+}
@@ -0,0 +1,156 @@
+package compile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskCache(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	c := diskCache{d, []byte("compiler")}
+	s := []byte(`(def (f x) x)`)
+	cc := newCompiler(builtinsEnvironment(), newModulesCache())
+	m, err := cc.desugarSubModule("/foo", s)
+	assert.Nil(t, err)
+
+	_, ok := c.Get("/foo", s)
+	assert.False(t, ok)
+
+	assert.Nil(t, c.Set("/foo", s, m))
+
+	n, ok := c.Get("/foo", s)
+	assert.True(t, ok)
+	assert.Equal(t, encodeNodes(m), encodeNodes(n))
+
+	_, ok = c.Get("/foo", []byte(`(def (f x) 42)`))
+	assert.False(t, ok)
+
+	_, ok = c.Get("/bar", s)
+	assert.False(t, ok)
+}
+
+func TestDiskCacheWithDifferentCompiler(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	s := []byte(`(def (f x) x)`)
+	cc := newCompiler(builtinsEnvironment(), newModulesCache())
+	m, err := cc.desugarSubModule("/foo", s)
+	assert.Nil(t, err)
+
+	assert.Nil(t, diskCache{d, []byte("foo")}.Set("/foo", s, m))
+
+	_, ok := diskCache{d, []byte("foo")}.Get("/foo", s)
+	assert.True(t, ok)
+
+	_, ok = diskCache{d, []byte("bar")}.Get("/foo", s)
+	assert.False(t, ok)
+}
+
+func TestDiskCacheWithChangedImport(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	c := diskCache{filepath.Join(d, consts.CacheDirectory), []byte("compiler")}
+	p := filepath.Join(d, "foo")
+	f := filepath.Join(d, "bar"+consts.FileExtension)
+	s := []byte(`(import "./bar") (def (f x) (bar.g x))`)
+
+	assert.Nil(t, ioutil.WriteFile(f, []byte(`(def (g x) x)`), 0600))
+
+	cc := newCompiler(builtinsEnvironment(), newModulesCache())
+	m, err := cc.desugarSubModule(p, s)
+	assert.Nil(t, err)
+
+	assert.Nil(t, c.Set(p, s, m))
+
+	_, ok := c.Get(p, s)
+	assert.True(t, ok)
+
+	assert.Nil(t, ioutil.WriteFile(f, []byte(`(def (g x) 42)`), 0600))
+
+	_, ok = c.Get(p, s)
+	assert.False(t, ok)
+
+	assert.Nil(t, os.Remove(f))
+
+	_, ok = c.Get(p, s)
+	assert.False(t, ok)
+}
+
+func TestDiskCacheDisabled(t *testing.T) {
+	c := diskCache{}
+
+	assert.Nil(t, c.Set("/foo", nil, nil))
+
+	_, ok := c.Get("/foo", nil)
+	assert.False(t, ok)
+}
+
+func TestDiskCacheWithBrokenEntry(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	c := diskCache{d, []byte("compiler")}
+	assert.Nil(t, ioutil.WriteFile(c.filename("/foo"), []byte("foo"), 0600))
+
+	_, ok := c.Get("/foo", nil)
+	assert.False(t, ok)
+}
+
+func TestNewDiskCache(t *testing.T) {
+	h := os.Getenv("HOME")
+	defer os.Setenv("HOME", h)
+	v := os.Getenv(consts.PathName)
+	defer os.Setenv(consts.PathName, v)
+
+	for _, c := range []struct{ home, path, directory string }{
+		{"", "", ""},
+		{"/foo", "", filepath.FromSlash("/foo/" + consts.LanguageDirectory + "/" + consts.CacheDirectory)},
+		{"/foo", "/bar:/baz", filepath.FromSlash("/foo/" + consts.LanguageDirectory + "/" + consts.CacheDirectory)},
+	} {
+		assert.Nil(t, os.Setenv("HOME", c.home))
+		assert.Nil(t, os.Setenv(consts.PathName, c.path))
+		assert.Equal(t, c.directory, newDiskCache().directory)
+	}
+}
+
+func TestCompileWithDiskCache(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	h := os.Getenv("HOME")
+	defer os.Setenv("HOME", h)
+	assert.Nil(t, os.Setenv("HOME", d))
+
+	v := os.Getenv(consts.PathName)
+	defer os.Setenv(consts.PathName, v)
+	assert.Nil(t, os.Setenv(consts.PathName, d))
+
+	f := filepath.Join(d, "foo")
+	assert.Nil(t, ioutil.WriteFile(f+consts.FileExtension, []byte(`(def (f x) x)`), 0600))
+
+	for i := 0; i < 2; i++ {
+		es, err := CompileSource("main.cloe", `(import "foo") (print (foo.f 42))`)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(es))
+	}
+
+	fs, err := ioutil.ReadDir(filepath.Join(d, consts.LanguageDirectory, consts.CacheDirectory))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fs))
+}
@@ -1,16 +1,20 @@
 package compile
 
-import "github.com/cloe-lang/cloe/src/lib/core"
+import (
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
 
 // Effect represents an effect of a program.
 type Effect struct {
 	value    core.Value
 	expanded bool
+	info     *debug.Info
 }
 
 // NewEffect creates an effect.
-func NewEffect(value core.Value, expanded bool) Effect {
-	return Effect{value, expanded}
+func NewEffect(value core.Value, expanded bool, i *debug.Info) Effect {
+	return Effect{value, expanded, i}
 }
 
 // Value returns an effect of a thunk.
@@ -22,3 +26,8 @@ func (o Effect) Value() core.Value {
 func (o Effect) Expanded() bool {
 	return o.expanded
 }
+
+// DebugInfo returns debug information of a location where an effect is written.
+func (o Effect) DebugInfo() *debug.Info {
+	return o.info
+}
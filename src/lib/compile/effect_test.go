@@ -8,14 +8,14 @@ import (
 )
 
 func TestNewEffect(t *testing.T) {
-	t.Log(NewEffect(core.Nil, false))
+	t.Log(NewEffect(core.Nil, false, nil))
 }
 
 func TestEffectValue(t *testing.T) {
-	assert.NotEqual(t, nil, NewEffect(core.Nil, false).Value())
+	assert.NotEqual(t, nil, NewEffect(core.Nil, false, nil).Value())
 }
 
 func TestEffectExpanded(t *testing.T) {
-	assert.False(t, NewEffect(core.Nil, false).Expanded())
-	assert.True(t, NewEffect(core.EmptyList, true).Expanded())
+	assert.False(t, NewEffect(core.Nil, false, nil).Expanded())
+	assert.True(t, NewEffect(core.EmptyList, true, nil).Expanded())
 }
@@ -23,17 +23,25 @@ func (e *environment) set(s string, t core.Value) {
 }
 
 func (e environment) get(s string) core.Value {
-	if t, ok := e.me[s]; ok {
-		return t
+	t, err := e.lookup(s)
+
+	if err != nil {
+		panic(err)
 	}
 
-	t, err := e.fallback(s)
+	return t
+}
+
+func (e environment) lookup(s string) (core.Value, error) {
+	if t, ok := e.me[s]; ok {
+		return t, nil
+	}
 
-	if err == nil {
-		return t
+	if t, err := e.fallback(s); err == nil {
+		return t, nil
 	}
 
-	panic(fmt.Errorf("The name, %s is not found", s))
+	return nil, fmt.Errorf("the name, %s is not found", s)
 }
 
 func (e environment) toMap() module {
@@ -0,0 +1,155 @@
+package compile
+
+import (
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// privatePrefix is a prefix of names of private members in modules. They are
+// kept in modules only to report references to them as errors.
+const privatePrefix = "$private:"
+
+// exports returns a set of names listed in export statements of a module. It
+// returns nil if the module has no export statement.
+func exports(m []interface{}) map[string]bool {
+	var ns map[string]bool
+
+	for _, s := range m {
+		if e, ok := s.(ast.Export); ok {
+			if ns == nil {
+				ns = map[string]bool{}
+			}
+
+			for _, n := range e.Names() {
+				ns[n] = true
+			}
+		}
+	}
+
+	return ns
+}
+
+// isExported checks if a name defined in a module is exported. Names are
+// exported if they are listed in export statements or, when a module has no
+// export statement, if they do not begin with `_`.
+func isExported(n string, es map[string]bool) bool {
+	if strings.HasPrefix(n, "$") {
+		return false
+	} else if es != nil {
+		return es[n]
+	}
+
+	return !strings.HasPrefix(n, "_")
+}
+
+// IsExported checks if a name defined in a module of statements is exported.
+func IsExported(m []interface{}, n string) bool {
+	return isExported(n, exports(m))
+}
+
+// definedNames returns names of values defined at the top level of a
+// desugared module.
+func definedNames(m []interface{}) []string {
+	ns := []string{}
+
+	for _, s := range m {
+		switch x := s.(type) {
+		case ast.LetVar:
+			ns = append(ns, x.Name())
+		case ast.DefFunction:
+			ns = append(ns, x.Name())
+		}
+	}
+
+	return ns
+}
+
+// checkExports checks if all names listed in export statements of a module
+// are defined in it.
+func checkExports(m []interface{}, ns []string) error {
+	ds := make(map[string]bool, len(ns))
+
+	for _, n := range ns {
+		ds[n] = true
+	}
+
+	es := []error{}
+
+	for _, s := range m {
+		e, ok := s.(ast.Export)
+
+		if !ok {
+			continue
+		}
+
+		for _, n := range e.Names() {
+			if !ds[n] {
+				es = append(es, debug.NewError(
+					e.DebugInfo(),
+					"ExportError",
+					"the name, %s is not defined in the module",
+					n))
+			}
+		}
+	}
+
+	if len(es) != 0 {
+		return debug.Errors(es)
+	}
+
+	return nil
+}
+
+// checkImportedNames checks if names imported selectively are exported by a
+// module.
+func checkImportedNames(i ast.Import, exported func(string) bool) error {
+	es := []error{}
+
+	for _, n := range i.Names() {
+		if !exported(n.Name()) {
+			es = append(es, debug.NewError(
+				i.DebugInfo(),
+				"ImportError",
+				"the name, %s is not exported by the module, %s",
+				n.Name(),
+				i.Path()))
+		}
+	}
+
+	if len(es) != 0 {
+		return debug.Errors(es)
+	}
+
+	return nil
+}
+
+// failedImportNames returns prefixes of names, or names themselves imported
+// selectively, whose references are not reported on failed imports.
+func failedImportNames(i ast.Import) []string {
+	if i.Data() {
+		return []string{i.Prefix()}
+	} else if i.Names() == nil {
+		return []string{i.Prefix() + "."}
+	}
+
+	ns := make([]string, 0, len(i.Names()))
+
+	for _, n := range i.Names() {
+		ns = append(ns, n.Alias())
+	}
+
+	return ns
+}
+
+// isFailedImportName checks if a name is imported by failed imports.
+func isFailedImportName(s string, ns []string) bool {
+	for _, n := range ns {
+		if s == n || strings.HasSuffix(n, ".") && strings.HasPrefix(s, n) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,164 @@
+package compile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	godebug "runtime/debug"
+)
+
+const (
+	goModulePath   = "github.com/cloe-lang/cloe"
+	develGoVersion = "(devel)"
+)
+
+var goVersionPattern = regexp.MustCompile(`^go(\d+\.\d+)`)
+
+// BuildGo compiles a main module into Go source code and builds a static
+// executable of a path from it with the Go toolchain.
+func BuildGo(p, o string) error {
+	s, err := GenerateGo(p)
+
+	if err != nil {
+		return err
+	}
+
+	o, err = filepath.Abs(o)
+
+	if err != nil {
+		return err
+	}
+
+	d, err := ioutil.TempDir("", "cloe")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(d)
+
+	m := goModFile()
+
+	if err := ioutil.WriteFile(filepath.Join(d, "main.go"), []byte(s), 0644); err != nil {
+		return err
+	} else if m == "" {
+		return runGo(d, false, "build", "-o", o, ".")
+	} else if err := ioutil.WriteFile(filepath.Join(d, "go.mod"), []byte(m), 0644); err != nil {
+		return err
+	}
+
+	for _, as := range [][]string{{"mod", "tidy"}, {"build", "-o", o, "."}} {
+		if err := runGo(d, true, as...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// goModFile returns a go.mod file of a generated program which requires the
+// cloe module linked into the running program. Development builds replace it
+// with its local source directory and dependencies with their replacements in
+// the build. It returns an empty string if the running program is built in
+// GOPATH mode.
+func goModFile() string {
+	i, ok := godebug.ReadBuildInfo()
+
+	if !ok || i.Main.Path == "" {
+		return ""
+	}
+
+	v, d := goModuleVersion(i)
+	m := "module main\n"
+
+	// Module graphs are pruned with language versions so that only modules
+	// providing packages need to be resolved.
+	if ss := goVersionPattern.FindStringSubmatch(i.GoVersion); ss != nil {
+		m += fmt.Sprintf("\ngo %s\n", ss[1])
+	}
+
+	m += fmt.Sprintf("\nrequire %s %s\n", goModulePath, v)
+
+	if d == "" {
+		return m
+	}
+
+	m += fmt.Sprintf("\nreplace %s => %s\n", goModulePath, d)
+
+	for _, s := range goModuleReplacements(i, d) {
+		m += s + "\n"
+	}
+
+	return m
+}
+
+// goModuleVersion returns a version of the cloe module linked into a program
+// and a local directory replacing it if any.
+func goModuleVersion(i *godebug.BuildInfo) (string, string) {
+	for _, m := range append([]*godebug.Module{&i.Main}, i.Deps...) {
+		if m.Path != goModulePath {
+			continue
+		} else if m.Replace != nil && localGoModule(m.Replace) {
+			return "v0.0.0", m.Replace.Path
+		} else if m.Replace != nil {
+			return m.Replace.Version, ""
+		} else if m.Version != "" && m.Version != develGoVersion {
+			return m.Version, ""
+		}
+	}
+
+	_, f, _, _ := runtime.Caller(0)
+	return "v0.0.0", filepath.Join(filepath.Dir(f), "..", "..", "..")
+}
+
+// goModuleReplacements returns replace directives of dependencies in a build
+// because ones in a local cloe module are ignored by the Go toolchain.
+// Relative paths of replacements are resolved in the local directory.
+func goModuleReplacements(i *godebug.BuildInfo, d string) []string {
+	ss := []string{}
+
+	for _, m := range i.Deps {
+		r := m.Replace
+
+		if r == nil || m.Path == goModulePath {
+			continue
+		}
+
+		p, v := r.Path, " "+r.Version
+
+		if localGoModule(r) && !filepath.IsAbs(p) {
+			p, v = filepath.Join(d, p), ""
+		} else if localGoModule(r) {
+			v = ""
+		}
+
+		ss = append(ss, fmt.Sprintf("replace %s => %s%s", m.Path, p, v))
+	}
+
+	return ss
+}
+
+// localGoModule checks if a module is in a local directory.
+func localGoModule(m *godebug.Module) bool {
+	return m.Version == "" || m.Version == develGoVersion
+}
+
+func runGo(d string, module bool, as ...string) error {
+	m := "off"
+
+	if module {
+		m = "on"
+	}
+
+	c := exec.Command("go", as...)
+	c.Dir = d
+	c.Env = append(os.Environ(), "CGO_ENABLED=0", "GO111MODULE="+m)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	return c.Run()
+}
@@ -0,0 +1,73 @@
+package compile
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	godebug "runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildGo(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command is not found")
+	}
+
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	p := filepath.Join(d, "main.cloe")
+	err = ioutil.WriteFile(p, []byte(`(def (f x) (+ x 1)) (print (f 41))`), 0644)
+	assert.Nil(t, err)
+
+	o := filepath.Join(d, "main")
+	assert.Nil(t, BuildGo(p, o))
+
+	bs, err := exec.Command(o).Output()
+	assert.Nil(t, err)
+	assert.Equal(t, "42", strings.TrimSpace(string(bs)))
+}
+
+func TestBuildGoWithInvalidSource(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	p := filepath.Join(d, "main.cloe")
+	err = ioutil.WriteFile(p, []byte(`(print x)`), 0644)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, BuildGo(p, filepath.Join(d, "main")))
+}
+
+func TestGoModFile(t *testing.T) {
+	m := goModFile()
+
+	if i, ok := godebug.ReadBuildInfo(); !ok || i.Main.Path == "" {
+		assert.Equal(t, "", m)
+		return
+	}
+
+	assert.True(t, strings.HasPrefix(m, "module main\n"))
+	assert.Contains(t, m, "\nrequire github.com/cloe-lang/cloe ")
+}
+
+func TestGoModuleReplacements(t *testing.T) {
+	i := &godebug.BuildInfo{Deps: []*godebug.Module{
+		{Path: "example.com/foo", Version: "v1.0.0"},
+		{Path: "example.com/bar", Replace: &godebug.Module{Path: "/bar"}},
+		{Path: "example.com/baz", Replace: &godebug.Module{Path: "baz", Version: "(devel)"}},
+		{Path: "example.com/qux", Replace: &godebug.Module{Path: "example.com/quux", Version: "v2.0.0"}},
+	}}
+
+	assert.Equal(t, []string{
+		"replace example.com/bar => /bar",
+		"replace example.com/baz => " + filepath.Join("/cloe", "baz"),
+		"replace example.com/qux => example.com/quux v2.0.0",
+	}, goModuleReplacements(i, "/cloe"))
+}
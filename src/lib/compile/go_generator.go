@@ -0,0 +1,621 @@
+package compile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/desugar"
+	"github.com/cloe-lang/cloe/src/lib/modules"
+	"github.com/cloe-lang/cloe/src/lib/parse"
+	"github.com/cloe-lang/cloe/src/lib/scalar"
+)
+
+// goBuiltinExpressions are Go expressions of built-in functions in goBuiltins.
+var goBuiltinExpressions = map[string]string{
+	"if": "core.If",
+
+	"partial": "core.Partial",
+
+	"first": "core.First",
+	"rest":  "core.Rest",
+
+	"typeOf":   "core.TypeOf",
+	"ordered?": "core.IsOrdered",
+
+	"+":   "core.Add",
+	"-":   "core.Sub",
+	"*":   "core.Mul",
+	"/":   "core.Div",
+	"//":  "core.FloorDiv",
+	"mod": "core.Mod",
+	"**":  "core.Pow",
+
+	"=":  "core.Equal",
+	"<":  "builtins.Less",
+	"<=": "builtins.LessEq",
+	">":  "builtins.Greater",
+	">=": "builtins.GreaterEq",
+
+	"toString": "core.ToString",
+	"dump":     "core.Dump",
+
+	"@":       "core.Index",
+	"delete":  "core.Delete",
+	"include": "core.Include",
+	"insert":  "core.Insert",
+	"merge":   "core.Merge",
+	"size":    "core.Size",
+	"toList":  "core.ToList",
+
+	"par":   "builtins.Par",
+	"seq":   "builtins.Seq",
+	"seq!":  "builtins.EffectSeq",
+	"rally": "builtins.Rally",
+
+	"read":  "builtins.Read",
+	"print": "builtins.Print",
+
+	"error": "core.Error",
+	"catch": "core.Catch",
+
+	"pure": "core.Pure",
+
+	"implement": "core.Implement",
+}
+
+// goModule is a module of names mapped to Go expressions.
+type goModule map[string]string
+
+// goProgram is a Go program generated from modules. Values in modules are
+// declared as package-level variables.
+type goProgram struct {
+	declarations []string
+	constants    map[string]string
+	modules      map[string]goModule
+	builtins     goModule
+	usesModules  bool
+	cache        modulesCache // modules compiled to expand macros
+}
+
+func newGoProgram() *goProgram {
+	p := &goProgram{constants: map[string]string{}, modules: map[string]goModule{}, cache: newModulesCache()}
+	p.builtins = p.generateBuiltins()
+	return p
+}
+
+// declare declares a variable of a Go expression and returns its name.
+func (p *goProgram) declare(prefix, expr string) string {
+	n := prefix + strconv.Itoa(len(p.declarations))
+	p.declarations = append(p.declarations, n+" = "+expr)
+	return n
+}
+
+// constant declares a constant variable of a Go expression only once.
+func (p *goProgram) constant(prefix, expr string) string {
+	if n, ok := p.constants[expr]; ok {
+		return n
+	}
+
+	n := p.declare(prefix, expr)
+	p.constants[expr] = n
+	return n
+}
+
+func (p *goProgram) generateBuiltins() goModule {
+	m := goModule{}
+
+	for s, e := range goBuiltinExpressions {
+		m[s] = e
+		m["$"+s] = e
+	}
+
+	m["$matchError"] = p.constant("v", fmt.Sprintf("core.NewError(%q, %q)", "MatchError", matchErrorMessage))
+	m["$constructor"] = "core.Constructor"
+	m["$fields"] = "core.Fields"
+	m["$instance?"] = "core.IsInstance"
+	m["$y"] = "builtins.Y"
+	m["$ys"] = "builtins.Ys"
+
+	for n, e := range p.generateBuiltinModule(m.copy(), internalBuiltinsSource) {
+		m["$"+n] = e
+	}
+
+	for n, e := range p.generateBuiltinModule(m.copy(), builtinsSource) {
+		m[n] = e
+		m["$"+n] = e
+	}
+
+	return m
+}
+
+func (p *goProgram) generateBuiltinModule(m goModule, source string) goModule {
+	a, err := parse.SubModule(builtinsFilename, source)
+
+	if err != nil {
+		panic(err)
+	}
+
+	g := p.newGenerator(m)
+
+	if _, err := g.generateModule(desugar.Desugar(a), "INVALID"); err != nil {
+		panic(err)
+	}
+
+	return g.env
+}
+
+func (p *goProgram) newGenerator(m goModule) goGenerator {
+	return goGenerator{program: p, env: m}
+}
+
+// source renders a main package running effects.
+func (p *goProgram) source(es []string) (string, error) {
+	b := &bytes.Buffer{}
+
+	fmt.Fprint(b, "// Code generated by cloe build. DO NOT EDIT.\n\npackage main\n\nimport (\n")
+
+	for _, s := range []string{"builtins", "compile", "core", "debug", "modules", "run"} {
+		if s != "modules" || p.usesModules {
+			fmt.Fprintf(b, "%q\n", "github.com/cloe-lang/cloe/src/lib/"+s)
+		}
+	}
+
+	fmt.Fprint(b, ")\n\nvar (\n")
+
+	for _, d := range p.declarations {
+		fmt.Fprintln(b, d)
+	}
+
+	fmt.Fprintf(b, ")\n\nfunc main() {\nrun.Run(%s)\n}\n", goSlice("compile.Effect", es))
+
+	bs, err := format.Source(b.Bytes())
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(bs), nil
+}
+
+// goGenerator generates Go expressions from a desugared module.
+type goGenerator struct {
+	program       *goProgram
+	env           goModule
+	info          *debug.Info
+	errors        []error
+	failedImports []string
+	imports       importStack
+}
+
+// goScope is a scope of arguments and local variables in a function.
+type goScope struct {
+	variables  map[string]string
+	references map[string]bool
+}
+
+// GenerateGo generates Go source code of a main package which runs a main
+// module of a path with its imported modules.
+func GenerateGo(p string) (string, error) {
+	q, s, err := readFileOrStdin(p)
+
+	if err != nil {
+		return "", err
+	}
+
+	m, err := parse.MainModule(q, s)
+
+	if err != nil {
+		return "", err
+	}
+
+	pr := newGoProgram()
+	d := filepath.ToSlash(path.Dir(p))
+	c := newCompiler(builtinsEnvironment(), pr.cache)
+	c.imports = newImportStack(q)
+	m, err = c.desugar(m, d)
+
+	if err != nil {
+		return "", err
+	}
+
+	g := pr.newGenerator(pr.builtins.copy())
+	g.imports = c.imports
+	es, err := g.generateModule(m, d)
+
+	if err != nil {
+		return "", err
+	}
+
+	return pr.source(es)
+}
+
+// generateModule generates Go expressions of values in a module and returns
+// ones of its effects.
+func (g *goGenerator) generateModule(m []interface{}, d string) ([]string, error) {
+	es := []string{}
+
+	for _, s := range m {
+		g.info = nil
+
+		switch x := s.(type) {
+		case ast.LetVar:
+			g.env[x.Name()] = g.thunk(x.Expr())
+		case ast.DefFunction:
+			g.info = x.DebugInfo()
+			g.env[x.Name()] = g.program.declare("v", g.function(x))
+		case ast.Effect:
+			es = append(es, fmt.Sprintf(
+				"compile.NewEffect(%s, %t, %s)",
+				g.thunk(x.Expr()),
+				x.Expanded(),
+				g.debugInfo(x.DebugInfo())))
+		case ast.Import:
+			m, err := g.importModule(x, d)
+
+			if err == nil {
+				err = checkImportedNames(x, func(n string) bool {
+					_, ok := m[n]
+					return ok
+				})
+			}
+
+			if err != nil {
+				g.importError(x, err)
+				continue
+			}
+
+			for k, v := range m {
+				if l, ok := x.LocalName(k); ok {
+					g.env[l] = v
+				}
+			}
+		case ast.Export:
+		default:
+			panic(fmt.Errorf("Invalid type: %#v", x))
+		}
+	}
+
+	if len(g.errors) != 0 {
+		es := g.errors
+		g.errors = nil
+		return nil, debug.Errors(es)
+	}
+
+	return es, nil
+}
+
+func (g *goGenerator) importError(i ast.Import, err error) {
+	g.failedImports = append(g.failedImports, failedImportNames(i)...)
+
+	switch err := err.(type) {
+	case debug.Errors:
+		g.errors = append(g.errors, err...)
+	case *debug.Error:
+		g.errors = append(g.errors, err)
+	default:
+		g.errors = append(g.errors, debug.NewError(i.DebugInfo(), "ImportError", "%v", err))
+	}
+}
+
+func (g *goGenerator) importModule(i ast.Import, d string) (goModule, error) {
+	p := i.Path()
+
+	if m, ok := modules.Modules[p]; ok {
+		g.program.usesModules = true
+		gm := make(goModule, len(m))
+
+		for k := range m {
+			gm[k] = fmt.Sprintf("modules.Modules[%q][%q]", p, k)
+		}
+
+		return gm, nil
+	}
+
+	p, err := resolveModulePath(p, d)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if m, ok := g.program.modules[p]; ok {
+		return m, nil
+	} else if i.Data() {
+		m, err := g.goDataModule(p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		g.program.modules[p] = m
+
+		return m, nil
+	}
+
+	is, err := g.imports.push(p, i.DebugInfo())
+
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := g.generateSubModule(p, is)
+
+	if err != nil {
+		return nil, err
+	}
+
+	g.program.modules[p] = m
+
+	return m, nil
+}
+
+func (g *goGenerator) generateSubModule(p string, is importStack) (goModule, error) {
+	p = modulePath(p)
+	bs, err := ioutil.ReadFile(filepath.FromSlash(p + consts.FileExtension))
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := newCompiler(builtinsEnvironment(), g.program.cache)
+	c.imports = is
+	m, err := c.desugarSubModule(p, bs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	gg := g.program.newGenerator(g.program.builtins.copy())
+	gg.imports = is
+
+	if _, err := gg.generateModule(m, path.Dir(p)); err != nil {
+		return nil, err
+	}
+
+	es := exports(m)
+	gm := goModule{}
+
+	for _, n := range definedNames(m) {
+		if isExported(n, es) {
+			gm[n] = gg.env[n]
+		}
+	}
+
+	return gm, nil
+}
+
+// get resolves a name into a Go expression. It records an error and returns
+// a placeholder expression instead if the name is not found.
+func (g *goGenerator) get(s string) string {
+	if e, ok := g.env[s]; ok {
+		return e
+	}
+
+	if v, err := scalar.Convert(s); err == nil {
+		return g.scalar(v)
+	}
+
+	if isFailedImportName(s, g.failedImports) {
+		return "core.Nil"
+	}
+
+	g.errors = append(g.errors, debug.NewError(g.info, "NameError", "the name, %s is not found", s))
+
+	return "core.Nil"
+}
+
+func (g *goGenerator) scalar(v core.Value) string {
+	switch v {
+	case core.True:
+		return "core.True"
+	case core.False:
+		return "core.False"
+	case core.Nil:
+		return "core.Nil"
+	case core.EmptyList:
+		return "core.EmptyList"
+	case core.EmptyDictionary:
+		return "core.EmptyDictionary"
+	}
+
+	switch x := v.(type) {
+	case *core.NumberType:
+		return g.program.constant("c", "core.NewNumber("+strconv.FormatFloat(float64(*x), 'g', -1, 64)+")")
+	case core.StringType:
+		return g.program.constant("c", fmt.Sprintf("core.NewString(%q)", string(x)))
+	}
+
+	panic(fmt.Errorf("Invalid scalar value: %#v", v))
+}
+
+func (g *goGenerator) debugInfo(i *debug.Info) string {
+	if i == nil {
+		return "nil"
+	}
+
+	return g.program.constant("i", fmt.Sprintf(
+		"debug.NewInfo(%q, %d, %d, %q)",
+		i.File(),
+		i.LineNumber(),
+		i.LinePosition(),
+		i.Source()))
+}
+
+func (g *goGenerator) thunk(expr interface{}) string {
+	return g.program.declare("v", fmt.Sprintf(
+		"core.PApp(core.NewLazyFunction(core.NewSignature(nil, \"\", nil, \"\"), func(...core.Value) core.Value {\nreturn %s\n}))",
+		g.expression(nil, expr)))
+}
+
+// function generates a closure of a function. Arguments are elements of a
+// slice and only local variables referred from its body are declared.
+func (g *goGenerator) function(f ast.DefFunction) string {
+	sig := f.Signature()
+	s := &goScope{variables: map[string]string{}}
+
+	for n, i := range sig.NameToIndex() {
+		s.variables[n] = fmt.Sprintf("args[%d]", i)
+	}
+
+	ls := f.Lets()
+	es := make([]string, 0, len(ls))
+	rs := make([]map[string]bool, 0, len(ls))
+
+	for i, l := range ls {
+		v := l.(ast.LetVar)
+		s.references = map[string]bool{}
+		es = append(es, g.expression(s, v.Expr()))
+		rs = append(rs, s.references)
+		s.variables[v.Name()] = fmt.Sprintf("l%d", i)
+	}
+
+	s.references = map[string]bool{}
+	b := g.expression(s, f.Body())
+	used := s.references
+
+	for i := len(ls) - 1; i >= 0; i-- {
+		if used[fmt.Sprintf("l%d", i)] {
+			for r := range rs[i] {
+				used[r] = true
+			}
+		}
+	}
+
+	ss := make([]string, 0, len(ls)+1)
+
+	for i, e := range es {
+		if n := fmt.Sprintf("l%d", i); used[n] {
+			ss = append(ss, n+" := "+e)
+		}
+	}
+
+	ss = append(ss, "return "+b)
+
+	return fmt.Sprintf(
+		"core.NewLazyFunction(%s, func(args ...core.Value) core.Value {\n%s\n})",
+		g.signature(sig),
+		strings.Join(ss, "\n"))
+}
+
+func (g *goGenerator) signature(s ast.Signature) string {
+	os := make([]string, 0, len(s.Keywords()))
+
+	for _, o := range s.Keywords() {
+		if o.Required() {
+			os = append(os, fmt.Sprintf("core.NewRequiredParameter(%q)", o.Name()))
+		} else {
+			os = append(os, fmt.Sprintf("core.NewOptionalParameter(%q, %s)", o.Name(), g.thunk(o.DefaultValue())))
+		}
+	}
+
+	ps := make([]string, 0, len(s.Positionals()))
+
+	for _, p := range s.Positionals() {
+		ps = append(ps, strconv.Quote(p))
+	}
+
+	return fmt.Sprintf(
+		"core.NewSignature(%s, %q, %s, %q)",
+		goSlice("string", ps),
+		s.RestPositionals(),
+		goSlice("core.OptionalParameter", os),
+		s.RestKeywords())
+}
+
+func (g *goGenerator) expression(s *goScope, expr interface{}) string {
+	switch x := expr.(type) {
+	case string:
+		if s != nil {
+			if e, ok := s.variables[x]; ok {
+				s.references[e] = true
+				return e
+			}
+		}
+
+		return g.get(x)
+	case ast.App:
+		if i := x.DebugInfo(); i != nil {
+			defer func(i *debug.Info) { g.info = i }(g.info)
+			g.info = i
+		}
+
+		args := x.Arguments()
+
+		ps := make([]string, 0, len(args.Positionals()))
+		for _, p := range args.Positionals() {
+			ps = append(ps, fmt.Sprintf("core.NewPositionalArgument(%s, %t)", g.expression(s, p.Value()), p.Expanded()))
+		}
+
+		ks := make([]string, 0, len(args.Keywords()))
+		for _, k := range args.Keywords() {
+			ks = append(ks, fmt.Sprintf("core.NewKeywordArgument(%q, %s)", k.Name(), g.expression(s, k.Value())))
+		}
+
+		return fmt.Sprintf(
+			"core.AppWithInfo(%s, core.NewArguments(%s, %s), %s)",
+			g.expression(s, x.Function()),
+			goSlice("core.PositionalArgument", ps),
+			goSlice("core.KeywordArgument", ks),
+			g.debugInfo(x.DebugInfo()))
+	case ast.Switch:
+		return g.switchExpression(s, x)
+	}
+
+	panic(fmt.Errorf("Invalid type: %#v", expr))
+}
+
+// switchExpression generates a switch expression which looks up an index of
+// a matched case in a dictionary.
+func (g *goGenerator) switchExpression(s *goScope, x ast.Switch) string {
+	if x.DefaultCase() == nil {
+		panic(errors.New("Default cases must be provided in switch expressions"))
+	}
+
+	kvs := make([]string, 0, len(x.Cases()))
+	cs := make([]string, 0, len(x.Cases()))
+
+	for i, c := range x.Cases() {
+		kvs = append(kvs, fmt.Sprintf("{Key: %s, Value: core.NewNumber(%d)}", g.get(c.Pattern()), i))
+		cs = append(cs, fmt.Sprintf("case %d:\nreturn %s\n", i, g.expression(s, c.Value())))
+	}
+
+	d := g.program.declare("s", fmt.Sprintf(
+		"core.EvalPure(core.NewDictionary([]core.KeyValue{%s}))",
+		strings.Join(kvs, ", ")))
+
+	return fmt.Sprintf(
+		"func() core.Value {\nif n, ok := core.EvalPure(core.PApp(core.Index, %s, %s)).(*core.NumberType); ok {\nswitch *n {\n%s}\n}\nreturn %s\n}()",
+		d,
+		g.expression(s, x.Value()),
+		strings.Join(cs, ""),
+		g.expression(s, x.DefaultCase()))
+}
+
+func (m goModule) copy() goModule {
+	n := make(goModule, len(m))
+
+	for k, v := range m {
+		n[k] = v
+	}
+
+	return n
+}
+
+// goSlice renders a slice literal of Go expressions.
+func goSlice(t string, es []string) string {
+	if len(es) == 0 {
+		return "nil"
+	}
+
+	return fmt.Sprintf("[]%s{%s}", t, strings.Join(es, ", "))
+}
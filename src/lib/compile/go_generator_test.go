@@ -0,0 +1,179 @@
+package compile
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoBuiltinExpressions(t *testing.T) {
+	for s := range goBuiltins.toMap() {
+		if s[:1] == "$" {
+			if _, ok := goBuiltins.toMap()[s[1:]]; ok {
+				s = s[1:]
+			} else {
+				continue
+			}
+		}
+
+		_, ok := goBuiltinExpressions[s]
+		assert.True(t, ok, s)
+	}
+
+	for s := range goBuiltinExpressions {
+		_, err := goBuiltins.lookup(s)
+		assert.Nil(t, err)
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	for _, s := range []string{
+		`(print "Hello, world!")`,
+		`(def (f x ..xs . y 42 ..ys) (let z (+ x y)) (let w z) w) (print (f 1))`,
+		`(def (f x . !y) (+ x y)) (print (f 1 . y 2))`,
+		`(def (f x) (match x 1 "one" 2 "two" _ "many")) (print (f 2))`,
+		`(let x [1 2 3]) ..(map print x)`,
+		`(import "re") (print (re.match "a" "a"))`,
+		`(import "re" (match . as m)) (print (m "a" "a"))`,
+		`(deftype shape (circle radius)) (print (match (circle 1) (circle r) r))`,
+	} {
+		f, err := ioutil.TempFile("", "")
+		assert.Nil(t, err)
+
+		f.WriteString(s)
+
+		err = f.Close()
+		assert.Nil(t, err)
+
+		g, err := GenerateGo(f.Name())
+
+		assert.Nil(t, err)
+
+		_, err = parser.ParseFile(token.NewFileSet(), "main.go", g, 0)
+
+		assert.Nil(t, err)
+	}
+}
+
+func TestGenerateGoWithUnknownNames(t *testing.T) {
+	f, err := ioutil.TempFile("", "")
+	assert.Nil(t, err)
+
+	f.WriteString("(def (f x) (g x))\n(print (h 42))")
+
+	err = f.Close()
+	assert.Nil(t, err)
+
+	_, err = GenerateGo(f.Name())
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(es))
+
+	for i, e := range es {
+		assert.Equal(t, "NameError", e.(*debug.Error).Name())
+		assert.Equal(t, i+1, e.(*debug.Error).Info().LineNumber())
+	}
+}
+
+func TestGenerateGoWithSubModule(t *testing.T) {
+	m := createModuleScript(t)
+
+	f, err := ioutil.TempFile("", "")
+	assert.Nil(t, err)
+
+	f.WriteString(fmt.Sprintf(
+		`(import "%v") (import m "%v") (print (%v.hello "John") (m.hello "Jane"))`,
+		m, m, path.Base(m)))
+
+	err = f.Close()
+	assert.Nil(t, err)
+
+	g, err := GenerateGo(f.Name())
+
+	assert.Nil(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", g, 0)
+
+	assert.Nil(t, err)
+}
+
+func TestGenerateGoWithInvalidPath(t *testing.T) {
+	_, err := GenerateGo("I'm the invalid path.")
+	assert.NotNil(t, err)
+}
+
+func TestGenerateGoWithInvalidImport(t *testing.T) {
+	f, err := ioutil.TempFile("", "")
+	assert.Nil(t, err)
+
+	f.WriteString(`(import "/nonExistentModule") (print (nonExistentModule.f 42))`)
+
+	err = f.Close()
+	assert.Nil(t, err)
+
+	_, err = GenerateGo(f.Name())
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(es))
+	assert.Equal(t, "ImportError", es[0].(*debug.Error).Name())
+}
+
+func TestGenerateGoWithDataFiles(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	f := filepath.Join(d, "main"+consts.FileExtension)
+	assert.Nil(t, ioutil.WriteFile(
+		f,
+		[]byte(`(import "./foo.json") (import "./bar.txt") (print (@ foo "baz") bar)`),
+		0600))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(d, "foo.json"), []byte(`{"baz": 42}`), 0600))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(d, "bar.txt"), []byte("\"bar\"\n"), 0600))
+
+	g, err := GenerateGo(f)
+	assert.Nil(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", g, 0)
+	assert.Nil(t, err)
+}
+
+func TestGenerateGoWithImportCycle(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	f := filepath.Join(d, "main"+consts.FileExtension)
+	assert.Nil(t, ioutil.WriteFile(f, []byte(`(import "./foo")`), 0600))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(d, "foo"+consts.FileExtension), []byte(`(import "./main")`), 0600))
+
+	_, err = GenerateGo(f)
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(es))
+	assert.Equal(t, "import cycle: main.cloe -> foo.cloe -> main.cloe", es[0].(*debug.Error).Message())
+}
+
+func TestGoProgramConstant(t *testing.T) {
+	p := newGoProgram()
+
+	assert.Equal(t, p.constant("c", "core.NewNumber(42)"), p.constant("c", "core.NewNumber(42)"))
+	assert.NotEqual(t, p.constant("c", "core.NewNumber(42)"), p.constant("c", "core.NewNumber(43)"))
+}
+
+func TestGoSlice(t *testing.T) {
+	assert.Equal(t, "nil", goSlice("string", nil))
+	assert.Equal(t, `[]string{"a", "b"}`, goSlice("string", []string{`"a"`, `"b"`}))
+}
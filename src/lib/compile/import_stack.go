@@ -0,0 +1,72 @@
+package compile
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// importStack is a stack of local modules being imported. It is used to
+// detect import cycles.
+type importStack []importFrame
+
+// importFrame is a module being imported with debug information of its import
+// statement.
+type importFrame struct {
+	path string
+	info *debug.Info
+}
+
+// newImportStack creates a stack whose bottom is a main module of a path.
+func newImportStack(p string) importStack {
+	if p == "" || p == "<stdin>" {
+		return nil
+	}
+
+	q, err := filepath.Abs(p)
+
+	if err != nil {
+		return nil
+	}
+
+	return importStack{{strings.TrimSuffix(q, consts.FileExtension), nil}}
+}
+
+// push pushes a module of an absolute path imported by a statement onto a
+// stack. It returns an error if the module is already being imported.
+func (s importStack) push(p string, i *debug.Info) (importStack, error) {
+	for k, f := range s {
+		if f.path == p {
+			return nil, s[k:].cycleError(p, i)
+		}
+	}
+
+	t := make(importStack, len(s), len(s)+1)
+	copy(t, s)
+
+	return append(t, importFrame{p, i}), nil
+}
+
+func (s importStack) cycleError(p string, i *debug.Info) error {
+	d := filepath.Dir(modulePath(s[0].path))
+	ss := make([]string, 0, len(s)+1)
+	is := make([]*debug.Info, 0, len(s))
+
+	for k, f := range append(s, importFrame{p, i}) {
+		q := modulePath(f.path) + consts.FileExtension
+
+		if r, err := filepath.Rel(d, q); err == nil {
+			q = filepath.ToSlash(r)
+		}
+
+		ss = append(ss, q)
+
+		if k != 0 {
+			is = append(is, f.info)
+		}
+	}
+
+	return debug.NewTracedError(is, "ImportError", "import cycle: %s", strings.Join(ss, " -> "))
+}
@@ -0,0 +1,33 @@
+package compile
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportStackPush(t *testing.T) {
+	s := newImportStack("/foo/main.cloe")
+	i := debug.NewInfo("/foo/main.cloe", 1, 1, `(import "./bar")`)
+	j := debug.NewInfo("/foo/bar", 1, 1, `(import "./main")`)
+
+	s, err := s.push("/foo/bar", i)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(s))
+
+	_, err = s.push("/foo/main", j)
+	e, ok := err.(*debug.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "ImportError", e.Name())
+	assert.Equal(t, "import cycle: main.cloe -> bar.cloe -> main.cloe", e.Message())
+	assert.Equal(t, []*debug.Info{i, j}, e.CallTrace())
+
+	_, err = s.push("/foo/bar", j)
+	assert.Equal(t, "import cycle: bar.cloe -> bar.cloe", err.(*debug.Error).Message())
+}
+
+func TestNewImportStackWithStdin(t *testing.T) {
+	assert.Equal(t, 0, len(newImportStack("")))
+	assert.Equal(t, 0, len(newImportStack("<stdin>")))
+}
@@ -0,0 +1,52 @@
+package compile
+
+import (
+	"fmt"
+
+	"github.com/cloe-lang/cloe/src/lib/parse"
+)
+
+const interpreterFilename = "<repl>"
+
+// Interpreter compiles pieces of source code one by one keeping definitions
+// in its environment between them.
+type Interpreter struct {
+	compiler compiler
+}
+
+// NewInterpreter creates an interpreter whose environment has only built-in
+// functions at first.
+func NewInterpreter() *Interpreter {
+	return &Interpreter{newCompiler(builtinsEnvironment(), newModulesCache())}
+}
+
+// Compile compiles statements in source into effects of thunks. Names defined
+// by the statements are available in the following calls.
+func (i *Interpreter) Compile(source string) (es []Effect, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			es = nil
+
+			switch x := r.(type) {
+			case error:
+				err = x
+			default:
+				err = fmt.Errorf("%v", x)
+			}
+		}
+	}()
+
+	m, err := parse.MainModule(interpreterFilename, source)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m, err = i.compiler.desugar(m, ".")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return i.compiler.compileModule(m, ".")
+}
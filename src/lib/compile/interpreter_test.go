@@ -0,0 +1,30 @@
+package compile
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpreterCompile(t *testing.T) {
+	i := NewInterpreter()
+
+	es, err := i.Compile(`(def (f x) (* x x)) (let y 3)`)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(es))
+
+	es, err = i.Compile(`(f y)`)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(es))
+	assert.Equal(t, core.NewNumber(9), core.EvalPure(es[0].Value()))
+}
+
+func TestInterpreterCompileError(t *testing.T) {
+	i := NewInterpreter()
+
+	for _, s := range []string{`(f`, `(f x)`, `(import "foo")`} {
+		_, err := i.Compile(s)
+		assert.NotNil(t, err)
+	}
+}
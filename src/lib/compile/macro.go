@@ -0,0 +1,94 @@
+package compile
+
+import (
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/desugar"
+	"github.com/cloe-lang/cloe/src/lib/gensym"
+)
+
+// macroPrefix is a prefix of names of macros exported by modules.
+const macroPrefix = "$macro:"
+
+// gensymFunction creates a unique name which never conflicts with ones in source code
+// for macros.
+var gensymFunction = core.NewLazyFunction(
+	core.NewSignature(nil, "", nil, ""),
+	func(...core.Value) core.Value {
+		return core.NewString(gensym.GenSym())
+	})
+
+// desugar desugars a module expanding macros imported or defined in it.
+func (c *compiler) desugar(m []interface{}, d string) ([]interface{}, error) {
+	if err := c.compileMacros(m, d); err != nil {
+		return nil, err
+	}
+
+	return desugar.DesugarWithMacros(m, c.macros)
+}
+
+// compileMacros compiles macros imported or defined in a module. Macros can
+// refer to built-in functions, imported modules and macros defined before
+// them. Errors of imports are reported on compilation of the module.
+func (c *compiler) compileMacros(m []interface{}, d string) error {
+	for _, s := range m {
+		switch x := s.(type) {
+		case ast.Import:
+			if m, err := c.importModule(x, d); err == nil {
+				c.importNames(x, m)
+			}
+		case ast.DefMacro:
+			f, err := c.compileMacro(x, d)
+
+			if err != nil {
+				return err
+			}
+
+			c.macros[x.Name()] = f
+			c.env.set(macroPrefix+x.Name(), f)
+			c.definedMacros = append(c.definedMacros, x.Name())
+		}
+	}
+
+	return nil
+}
+
+func (c *compiler) compileMacro(m ast.DefMacro, d string) (core.Value, error) {
+	ss, err := desugar.DesugarWithMacros([]interface{}{m.Function()}, c.macros)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cc := newCompiler(c.env.copy(), c.cache)
+	cc.env.set("gensym", gensymFunction)
+
+	if _, err := cc.compileModule(ss, d); err != nil {
+		return nil, err
+	}
+
+	return cc.env.get(m.Name()), nil
+}
+
+// expandsMacros checks if a module defines or uses macros. Desugared results
+// of such modules depend on other modules.
+func (c *compiler) expandsMacros(m []interface{}) bool {
+	b := false
+
+	for _, s := range m {
+		ast.Convert(func(x interface{}) interface{} {
+			switch x := x.(type) {
+			case ast.DefMacro:
+				b = true
+			case ast.App:
+				if n, ok := x.Function().(string); ok && c.macros[n] != nil {
+					b = true
+				}
+			}
+
+			return nil
+		}, s)
+	}
+
+	return b
+}
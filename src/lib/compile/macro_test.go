@@ -0,0 +1,127 @@
+package compile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileWithMacros(t *testing.T) {
+	for _, s := range []string{
+		`(defmacro (when c ..body) ["if" c ["seq" ..body] "nil"]) (print (when true 42))`,
+		`(defmacro (id x) x) (defmacro (id2 x) ["id" x]) (print (id2 42))`,
+		`(defmacro (let1 v body) (let x (gensym)) [{"parameters" [x] "body" body} v]) (print (let1 42 "foo"))`,
+		`(defmacro (swap f . x "1" y "2") [f y x]) (print (swap - . x 1 y 2))`,
+	} {
+		es, err := CompileSource("main.cloe", s)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(es))
+	}
+}
+
+func TestCompileWithMacroErrors(t *testing.T) {
+	for _, c := range []struct{ source, name string }{
+		{`(defmacro (f x) (error "MyError" "")) (print (f 42))`, "MyError"},
+		{`(defmacro (f x) nil) (print (f 42))`, "MacroError"},
+		{`(defmacro (f x) ["f" x]) (print (f 42))`, "MacroError"},
+		{`(defmacro (f x) x) (print (f ..[42]))`, "MacroError"},
+		{`(defmacro (f x) (g x)) (print (f 42))`, "NameError"},
+	} {
+		_, err := CompileSource("main.cloe", c.source)
+
+		es, ok := err.(debug.Errors)
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(es))
+		assert.Equal(t, c.name, es[0].(*debug.Error).Name())
+	}
+}
+
+func TestCompileWithHygienicMacros(t *testing.T) {
+	for _, c := range []struct {
+		source string
+		value  core.Value
+	}{
+		{
+			`(defmacro (myor a b) [{"parameters" ["t"] "body" ["if" "t" "t" b]} a]) (let t 1) (myor false t)`,
+			core.NewNumber(1),
+		},
+		{
+			`(defmacro (fn1 x body) [{"parameters" [x] "body" body} 41]) (fn1 t (+ t 1))`,
+			core.NewNumber(42),
+		},
+		{
+			`(defmacro (with-t body) [{"parameters" ["t"] "body" body} 0]) (let t 42) (with-t t)`,
+			core.NewNumber(42),
+		},
+	} {
+		es, err := CompileSource("main.cloe", c.source)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(es))
+		assert.Equal(t, c.value, core.EvalPure(es[0].Value()))
+	}
+}
+
+func TestCompileWithImportedMacros(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	assert.Nil(t, ioutil.WriteFile(
+		filepath.Join(d, "mod"+consts.FileExtension),
+		[]byte(`(def (double x) (* 2 x)) (defmacro (when c ..body) ["if" c ["seq" ..body] "nil"])`),
+		0600))
+
+	for _, s := range []string{
+		`(import "./mod") (mod.when true (mod.double 21))`,
+		`(import . "./mod") (when true (double 21))`,
+	} {
+		f := filepath.Join(d, "main"+consts.FileExtension)
+		assert.Nil(t, ioutil.WriteFile(f, []byte(s), 0600))
+
+		es, err := Compile(f)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(es))
+		assert.Equal(t, core.NewNumber(42), core.EvalPure(es[0].Value()))
+	}
+}
+
+func TestExpandsMacros(t *testing.T) {
+	c := newCompiler(builtinsEnvironment(), newModulesCache())
+	c.macros["when"] = core.Nil
+
+	for _, x := range []struct {
+		source string
+		answer bool
+	}{
+		{`(def (f x) x)`, false},
+		{`(def (f x) (when x 42))`, true},
+		{`(defmacro (f x) x)`, true},
+	} {
+		m, err := parse.SubModule("mod.cloe", x.source)
+		assert.Nil(t, err)
+		assert.Equal(t, x.answer, c.expandsMacros(m))
+	}
+}
+
+func TestInterpreterCompileWithMacros(t *testing.T) {
+	i := NewInterpreter()
+
+	es, err := i.Compile(`(defmacro (twice x) ["+" x x])`)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(es))
+
+	es, err = i.Compile(`(twice 21)`)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(es))
+	assert.Equal(t, core.NewNumber(42), core.EvalPure(es[0].Value()))
+}
@@ -0,0 +1,102 @@
+package compile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/consts"
+)
+
+// languagePath returns directories in the language path.
+func languagePath() ([]string, error) {
+	ds := []string{}
+
+	for _, d := range filepath.SplitList(os.Getenv(consts.PathName)) {
+		if d == "" {
+			continue
+		} else if !filepath.IsAbs(d) {
+			return nil, fmt.Errorf("%s in %s is not absolute", d, consts.PathName)
+		}
+
+		ds = append(ds, d)
+	}
+
+	return ds, nil
+}
+
+// LanguageDirectory returns a directory in a home directory where modules are
+// installed and compiled modules are cached. It is never one in the language
+// path so that directories there are not managed by tools.
+func LanguageDirectory() (string, error) {
+	d, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", errors.New("home directory is not found")
+	}
+
+	return filepath.Join(d, consts.LanguageDirectory), nil
+}
+
+// searchPath returns directories where modules imported in a directory are
+// searched in order. Vendor directories of the directory and its ancestors
+// take precedence over ones in the language path. Modules installed in the
+// language directory are searched at last.
+func searchPath(d string) ([]string, error) {
+	d, err := filepath.Abs(filepath.FromSlash(d))
+
+	if err != nil {
+		return nil, err
+	}
+
+	ds := []string{}
+
+	for {
+		if v := filepath.Join(d, consts.VendorDirectory); isDirectory(v) {
+			ds = append(ds, v)
+		}
+
+		p := filepath.Dir(d)
+
+		if p == d {
+			break
+		}
+
+		d = p
+	}
+
+	ls, err := languagePath()
+
+	if err != nil {
+		return nil, err
+	}
+
+	ds = append(ds, ls...)
+
+	if d, err := LanguageDirectory(); err == nil {
+		ds = append(ds, filepath.Join(d, consts.SourceDirectory))
+	}
+
+	return ds, nil
+}
+
+// moduleExists checks if a module of a path without a file extension or a
+// data file exists.
+func moduleExists(p string) bool {
+	if ast.IsDataFile(p) {
+		i, err := os.Stat(p)
+		return err == nil && !i.IsDir()
+	} else if isDirectory(p) {
+		p = filepath.Join(p, consts.ModuleFilename)
+	}
+
+	i, err := os.Stat(p + consts.FileExtension)
+	return err == nil && !i.IsDir()
+}
+
+func isDirectory(p string) bool {
+	i, err := os.Stat(p)
+	return err == nil && i.IsDir()
+}
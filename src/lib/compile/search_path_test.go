@@ -0,0 +1,94 @@
+package compile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageDirectory(t *testing.T) {
+	h := os.Getenv("HOME")
+	defer os.Setenv("HOME", h)
+	v := os.Getenv(consts.PathName)
+	defer os.Setenv(consts.PathName, v)
+
+	assert.Nil(t, os.Setenv("HOME", "/foo"))
+
+	for _, p := range []string{"", "/bar:/baz", "bar"} {
+		assert.Nil(t, os.Setenv(consts.PathName, p))
+		d, err := LanguageDirectory()
+		assert.Nil(t, err)
+		assert.Equal(t, filepath.Join("/foo", consts.LanguageDirectory), d)
+	}
+
+	assert.Nil(t, os.Setenv("HOME", ""))
+	_, err := LanguageDirectory()
+	assert.NotNil(t, err)
+}
+
+func TestResolveModulePathWithSearchPath(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	h := os.Getenv("HOME")
+	defer os.Setenv("HOME", h)
+	assert.Nil(t, os.Setenv("HOME", filepath.Join(d, "home")))
+
+	v := os.Getenv(consts.PathName)
+	defer os.Setenv(consts.PathName, v)
+
+	for _, f := range []string{
+		"project/vendor/foo",
+		"project/lib/vendor/bar",
+		"path1/foo",
+		"path1/baz",
+		"path2/baz",
+		"path2/qux/module",
+		"home/.cloe/src/example.com/quux",
+		"path1/src/example.com/corge",
+	} {
+		f = filepath.Join(d, filepath.FromSlash(f))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(f), 0700))
+		assert.Nil(t, ioutil.WriteFile(f+consts.FileExtension, nil, 0600))
+	}
+
+	assert.Nil(t, os.Setenv(
+		consts.PathName,
+		filepath.Join(d, "path1")+string(filepath.ListSeparator)+filepath.Join(d, "path2")))
+
+	for _, c := range []struct{ module, directory, path string }{
+		{"foo", "project/lib", "project/vendor/foo"},
+		{"bar", "project/lib", "project/lib/vendor/bar"},
+		{"foo", ".", "path1/foo"},
+		{"baz", "project", "path1/baz"},
+		{"qux", "project", "path2/qux"},
+		{"example.com/quux", "project", "home/.cloe/src/example.com/quux"},
+	} {
+		p, err := resolveModulePath(c.module, filepath.Join(d, filepath.FromSlash(c.directory)))
+		assert.Nil(t, err)
+		assert.Equal(t, filepath.Join(d, filepath.FromSlash(c.path)), p)
+	}
+
+	for _, m := range []string{"bar", "example.com/corge"} {
+		_, err = resolveModulePath(m, filepath.Join(d, "project"))
+		assert.NotNil(t, err)
+	}
+
+	for _, m := range []string{".", "..", "./foo", "../foo"} {
+		p, err := resolveModulePath(m, filepath.Join(d, "project"))
+		assert.Nil(t, err)
+		assert.Equal(t, filepath.Join(d, "project", filepath.FromSlash(m)), p)
+	}
+
+	_, err = resolveModulePath("", d)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, os.Setenv(consts.PathName, "foo"))
+	_, err = resolveModulePath("foo", d)
+	assert.NotNil(t, err)
+}
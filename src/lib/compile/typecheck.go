@@ -0,0 +1,155 @@
+package compile
+
+import (
+	"io/ioutil"
+	"path"
+	"path/filepath"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/desugar"
+	"github.com/cloe-lang/cloe/src/lib/parse"
+	"github.com/cloe-lang/cloe/src/lib/typecheck"
+	"github.com/cloe-lang/cloe/src/lib/types"
+)
+
+// Typecheck checks types in a main module of a path and modules imported by
+// it statically.
+func Typecheck(p string) error {
+	q, s, err := readFileOrStdin(p)
+
+	if err != nil {
+		return err
+	}
+
+	return TypecheckSource(q, s)
+}
+
+// TypecheckSource checks types in source code of a main module at a path
+// without reading the file.
+func TypecheckSource(p, s string) error {
+	m, err := parse.MainModule(p, s)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = newTypeChecker().checkModule(m, filepath.ToSlash(path.Dir(p)), newImportStack(p))
+	return err
+}
+
+// typeChecker checks types of modules caching types of names exported by
+// local modules.
+type typeChecker map[string]map[string]types.Type
+
+func newTypeChecker() typeChecker {
+	return typeChecker{}
+}
+
+func (t typeChecker) checkModule(m []interface{}, d string, is importStack) (map[string]types.Type, error) {
+	c := newCompiler(builtinsEnvironment(), newModulesCache())
+	c.imports = is
+
+	if err := c.compileMacros(m, d); err != nil {
+		return nil, err
+	}
+
+	m, err := desugar.DesugarSyntax(m, c.macros)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ms := map[string]map[string]types.Type{}
+
+	for _, s := range m {
+		if i, ok := s.(ast.Import); ok {
+			ts, err := t.importModule(i, d, is)
+
+			if err != nil {
+				return nil, importError(i, err)
+			}
+
+			ms[i.Path()] = ts
+		}
+	}
+
+	ts, err := typecheck.Check(m, ms)
+
+	if err != nil {
+		return nil, err
+	}
+
+	es := exports(m)
+
+	for n := range ts {
+		if !isExported(n, es) {
+			delete(ts, n)
+		}
+	}
+
+	return ts, nil
+}
+
+func (t typeChecker) importModule(i ast.Import, d string, is importStack) (map[string]types.Type, error) {
+	if ts, ok := typecheck.Modules[i.Path()]; ok {
+		return ts, nil
+	}
+
+	p, err := resolveModulePath(i.Path(), d)
+
+	if err != nil {
+		return nil, err
+	} else if ts, ok := t[p]; ok {
+		return ts, nil
+	} else if i.Data() {
+		ts, err := dataModuleTypes(p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		t[p] = ts
+
+		return ts, nil
+	}
+
+	is, err = is.push(p, i.DebugInfo())
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := modulePath(p)
+	bs, err := ioutil.ReadFile(filepath.FromSlash(q + consts.FileExtension))
+
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := parse.SubModule(q, string(bs))
+
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := t.checkModule(m, path.Dir(q), is)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t[p] = ts
+
+	return ts, nil
+}
+
+func importError(i ast.Import, err error) error {
+	switch err.(type) {
+	case debug.Errors, *debug.Error:
+		return err
+	}
+
+	return debug.NewError(i.DebugInfo(), "ImportError", "%v", err)
+}
@@ -0,0 +1,86 @@
+package compile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypecheck(t *testing.T) {
+	f, err := ioutil.TempFile("", "")
+	assert.Nil(t, err)
+
+	f.WriteString(`(def (f x:number) :number (+ x 1)) (print (f 42))`)
+
+	err = f.Close()
+	assert.Nil(t, err)
+
+	assert.Nil(t, Typecheck(f.Name()))
+}
+
+func TestTypecheckWithInvalidPath(t *testing.T) {
+	assert.NotNil(t, Typecheck("I'm the invalid path."))
+}
+
+func TestTypecheckSource(t *testing.T) {
+	for _, c := range []struct {
+		source string
+		ok     bool
+	}{
+		{`(def (f x:number) x) (print (f 42))`, true},
+		{`(def (f x:number) x) (print (f "foo"))`, false},
+		{`(import "json") (print (+ 1 (json.encode 42)))`, false},
+		{`(defmacro (twice x) ["+" x x]) (def (f) :number (twice 21))`, true},
+		{`(defmacro (twice x) ["+" x x]) (print (twice "foo"))`, false},
+		{`(print (+ 1 "foo"`, false},
+	} {
+		assert.Equal(t, c.ok, TypecheckSource("main.cloe", c.source) == nil, c.source)
+	}
+}
+
+func TestTypecheckWithSubModules(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	for n, s := range map[string]string{
+		"mod":  `(import "./mod2") (def (double x:number) :number (mod2.twice x))`,
+		"mod2": `(def (twice x:number) :number (* 2 x))`,
+		"bad":  `(def (f x:number) :string x)`,
+		"self": `(import "./self")`,
+		"mod3": `(export double) (def (twice x:number) :number (* 2 x)) (def (double x) (twice x))`,
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(d, n+consts.FileExtension), []byte(s), 0600))
+	}
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(d, "data.txt"), []byte("foo"), 0600))
+
+	for _, c := range []struct {
+		source, name string
+	}{
+		{`(import "./mod") (print (mod.double 21))`, ""},
+		{`(import "./mod") (import "./mod2") (print (mod.double (mod2.twice 21)))`, ""},
+		{`(import "./mod") (print (mod.double "foo"))`, "TypeError"},
+		{`(import "./mod" (double . as twice)) (print (twice "foo"))`, "TypeError"},
+		{`(import "./mod" (double)) (print (mod.double "foo"))`, ""},
+		{`(import "./mod3") (print (mod3.double "foo"))`, ""},
+		{`(import "./mod3") (print (mod3.twice "foo"))`, ""},
+		{`(import "./bad")`, "TypeError"},
+		{`(import "./none")`, "ImportError"},
+		{`(import "./self")`, "ImportError"},
+		{`(import "./data.txt") (print (+ data 1))`, "TypeError"},
+		{`(import "./data.txt") (print (merge data "foo"))`, ""},
+	} {
+		err := TypecheckSource(filepath.Join(d, "main.cloe"), c.source)
+
+		if c.name == "" {
+			assert.Nil(t, err)
+		} else {
+			assert.Contains(t, err.Error(), c.name+":")
+		}
+	}
+}
@@ -2,25 +2,57 @@ package consts
 
 // Names are predefined names used internally by desugarers and compilers.
 var Names = struct {
+	DataMember         string
 	DictionaryFunction string
 	EmptyDictionary    string
 	EmptyList          string
 	IndexFunction      string
 	ListFunction       string
+	OrPattern          string
 }{
+	DataMember:         "$data",
 	DictionaryFunction: "$dictionary",
 	EmptyDictionary:    "$emptyDictionary",
 	EmptyList:          "$emptyList",
 	IndexFunction:      "$@",
 	ListFunction:       "$list",
+	OrPattern:          "$orPattern",
 }
 
 // FileExtension is a file extension of the language.
 const FileExtension = ".cloe"
 
-// PathName is the name of the language path where modules are stored.
+// JSONFileExtension is a file extension of JSON data files which can be
+// imported.
+const JSONFileExtension = ".json"
+
+// TextFileExtension is a file extension of text data files which can be
+// imported.
+const TextFileExtension = ".txt"
+
+// TestFileSuffix is a suffix of names of test files.
+const TestFileSuffix = "_test" + FileExtension
+
+// PathName is the name of the language path where modules are stored. It is
+// a list of directories separated by colons.
 const PathName = "CLOE_PATH"
 
+// LanguageDirectory is the name of a directory in a home directory where
+// modules are installed and compiled modules are cached.
+const LanguageDirectory = ".cloe"
+
+// SourceDirectory is the name of a directory in the language directory where
+// modules are installed.
+const SourceDirectory = "src"
+
+// VendorDirectory is the name of directories in projects where modules are
+// vendored.
+const VendorDirectory = "vendor"
+
+// CacheDirectory is the name of a directory in the language directory where
+// compiled modules are cached.
+const CacheDirectory = "cache"
+
 // ModuleFilename is the name of the top level scripts in module directories
 // with no file extension.
 const ModuleFilename = "module"
@@ -56,6 +56,21 @@ func mergePositionalArguments(ps []PositionalArgument) Value {
 	return v
 }
 
+// deepen sets depths of evaluation of arguments.
+func (args Arguments) deepen(d int32) {
+	for _, v := range args.positionals {
+		deepen(v, d)
+	}
+
+	if args.expandedList != nil {
+		deepen(args.expandedList, d)
+	}
+
+	for _, k := range args.keywords {
+		deepen(k.value, d)
+	}
+}
+
 func (args *Arguments) nextPositional() Value {
 	if len(args.positionals) != 0 {
 		v := args.positionals[0]
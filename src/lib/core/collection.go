@@ -1,10 +1,16 @@
 package core
 
-type collection interface {
+// indexable is an interface for values whose elements can be extracted with
+// keys.
+type indexable interface {
 	Value
+	index(Value) Value
+}
+
+type collection interface {
+	indexable
 
 	include(Value) Value
-	index(Value) Value
 	insert(Value, Value) Value
 	merge(...Value) Value
 	delete(Value) Value
@@ -33,7 +39,7 @@ var Index = NewStrictFunction(
 		l := cons(vs[1], vs[2])
 
 		for !l.Empty() {
-			c, err := evalCollection(v)
+			c, err := evalIndexable(v)
 
 			if err != nil {
 				return err
@@ -0,0 +1,146 @@
+package core
+
+import "strings"
+
+// customMethods are names of functions which define behavior of custom
+// values as collections, strings, and ordered values.
+var customMethods = []string{
+	"include", "index", "insert", "merge", "delete", "toList", "size", // collection
+	"toString", // stringable
+	"compare",  // ordered
+}
+
+// CustomType represents a value whose behavior is defined by functions written
+// in the language.
+type CustomType struct {
+	name    string
+	methods map[string]Value
+}
+
+// Implement creates a custom value of a type name with functions implementing
+// built-in protocols.
+var Implement = NewLazyFunction(
+	NewSignature(
+		[]string{"type"}, "",
+		func() []OptionalParameter {
+			ps := make([]OptionalParameter, 0, len(customMethods))
+
+			for _, m := range customMethods {
+				ps = append(ps, NewOptionalParameter(m, Nil))
+			}
+
+			return ps
+		}(), ""),
+	func(vs ...Value) Value {
+		s, err := EvalString(vs[0])
+
+		if err != nil {
+			return err
+		}
+
+		switch s {
+		case "boolean", "dictionary", "function", "list", "nil", "number", "string":
+			return ValueError("type %s is already defined", s)
+		}
+
+		ms := map[string]Value{}
+
+		for i, m := range customMethods {
+			switch x := EvalPure(vs[i+1]).(type) {
+			case *ErrorType:
+				return x
+			case NilType:
+			case callable:
+				ms[m] = x
+			default:
+				return NotFunctionError(x)
+			}
+		}
+
+		return &CustomType{string(s), ms}
+	})
+
+func (c *CustomType) eval() Value {
+	return c
+}
+
+func (c *CustomType) implements(m string) bool {
+	_, ok := c.methods[m]
+	return ok
+}
+
+func (c *CustomType) call(m string, vs ...Value) Value {
+	f, ok := c.methods[m]
+
+	if !ok {
+		return NewError("TypeError", "%s does not implement %s", c.name, m)
+	}
+
+	return PApp(f, vs...)
+}
+
+func (c *CustomType) include(v Value) Value {
+	return c.call("include", v)
+}
+
+func (c *CustomType) index(v Value) Value {
+	return c.call("index", v)
+}
+
+func (c *CustomType) insert(k Value, v Value) Value {
+	return c.call("insert", k, v)
+}
+
+func (c *CustomType) merge(vs ...Value) Value {
+	return c.call("merge", vs...)
+}
+
+func (c *CustomType) delete(v Value) Value {
+	return c.call("delete", v)
+}
+
+func (c *CustomType) toList() Value {
+	return c.call("toList")
+}
+
+func (c *CustomType) size() Value {
+	return c.call("size")
+}
+
+func (c *CustomType) string() Value {
+	if !c.implements("toString") {
+		return NewString("<" + c.name + ">")
+	}
+
+	s, err := EvalString(c.call("toString"))
+
+	if err != nil {
+		return err
+	}
+
+	return s
+}
+
+func (c *CustomType) compare(x comparable) int {
+	cc := x.(*CustomType)
+
+	if c.name != cc.name {
+		return strings.Compare(c.name, cc.name)
+	} else if !c.implements("compare") {
+		panic(notComparableError(c))
+	}
+
+	n, err := EvalNumber(c.call("compare", cc))
+
+	if err != nil {
+		panic(err)
+	} else if n < 0 {
+		return -1
+	} else if n > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+func (*CustomType) ordered() {}
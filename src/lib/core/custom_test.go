@@ -0,0 +1,108 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestQueue creates a custom value which behaves like a list.
+func newTestQueue(vs ...Value) Value {
+	l := NewList(vs...)
+
+	method := func(ps []string, f func(...Value) Value) Value {
+		return NewLazyFunction(NewSignature(ps, "", nil, ""), f)
+	}
+
+	ks := []KeywordArgument{}
+
+	for n, f := range map[string]func(...Value) Value{
+		"include": func(vs ...Value) Value { return PApp(Include, l, vs[0]) },
+		"index":   func(vs ...Value) Value { return PApp(Index, l, vs[0]) },
+		"compare": func(vs ...Value) Value { return PApp(Compare, l, PApp(ToList, vs[0])) },
+	} {
+		ks = append(ks, NewKeywordArgument(n, method([]string{"arg"}, f)))
+	}
+
+	for n, f := range map[string]func(...Value) Value{
+		"size":     func(...Value) Value { return PApp(Size, l) },
+		"toList":   func(...Value) Value { return l },
+		"toString": func(...Value) Value { return PApp(Merge, NewString("queue"), PApp(Dump, l)) },
+	} {
+		ks = append(ks, NewKeywordArgument(n, method(nil, f)))
+	}
+
+	return App(Implement, NewArguments(
+		[]PositionalArgument{NewPositionalArgument(NewString("queue"), false)},
+		ks))
+}
+
+func TestImplement(t *testing.T) {
+	q := newTestQueue(NewNumber(1), NewNumber(2))
+
+	assert.Equal(t, NewNumber(2), EvalPure(PApp(Index, q, NewNumber(2))))
+	assert.Equal(t, True, EvalPure(PApp(Include, q, NewNumber(1))))
+	assert.Equal(t, NewNumber(2), EvalPure(PApp(Size, q)))
+	assert.True(t, testEqual(NewList(NewNumber(1), NewNumber(2)), PApp(ToList, q)))
+	assert.Equal(t, "queue[1 2]", string(EvalPure(PApp(ToString, q)).(StringType)))
+	assert.Equal(t, "[queue[1 2]]", string(EvalPure(PApp(Dump, NewList(q))).(StringType)))
+	assert.Equal(t, "queue", string(EvalPure(PApp(TypeOf, q)).(StringType)))
+}
+
+func TestImplementWithoutMethods(t *testing.T) {
+	v := PApp(Implement, NewString("foo"))
+
+	assert.Equal(t, "<foo>", string(EvalPure(PApp(Dump, v)).(StringType)))
+	assert.Equal(t, False, EvalPure(PApp(IsOrdered, v)))
+
+	for _, f := range []Value{Size, ToList} {
+		e, ok := EvalPure(PApp(f, v)).(*ErrorType)
+		assert.True(t, ok)
+		assert.Equal(t, "TypeError", e.Name())
+	}
+
+	for _, v := range []Value{
+		PApp(Compare, v, v),
+		PApp(Equal, v, v),
+		PApp(Insert, v, NewNumber(1), NewNumber(2)),
+		PApp(Merge, v, v),
+		PApp(Delete, v, NewNumber(1)),
+	} {
+		_, ok := EvalPure(v).(*ErrorType)
+		assert.True(t, ok)
+	}
+}
+
+func TestImplementError(t *testing.T) {
+	for _, v := range []Value{
+		PApp(Implement, NewNumber(42)),
+		PApp(Implement, NewString("list")),
+		App(Implement, NewArguments(
+			[]PositionalArgument{NewPositionalArgument(NewString("foo"), false)},
+			[]KeywordArgument{NewKeywordArgument("size", NewNumber(42))})),
+		App(Implement, NewArguments(
+			[]PositionalArgument{NewPositionalArgument(NewString("foo"), false)},
+			[]KeywordArgument{NewKeywordArgument("size", DummyError)})),
+	} {
+		_, ok := EvalPure(v).(*ErrorType)
+		assert.True(t, ok)
+	}
+}
+
+func TestCustomCompare(t *testing.T) {
+	q := newTestQueue(NewNumber(1))
+
+	assert.Equal(t, True, EvalPure(PApp(IsOrdered, q)))
+	assert.True(t, testEqual(q, newTestQueue(NewNumber(1))))
+	assert.Equal(t, -1, testCompare(q, newTestQueue(NewNumber(2))))
+	assert.Equal(t, 1, testCompare(q, newTestQueue()))
+	assert.Equal(t, -1, compare(EvalPure(q), EvalPure(PApp(Implement, NewString("r")))))
+
+	for _, v := range []Value{
+		PApp(Compare, q, NewNumber(1)),
+		PApp(Compare, q, PApp(Implement, NewString("r"))),
+	} {
+		_, ok := EvalPure(v).(*ErrorType)
+		assert.True(t, ok)
+	}
+}
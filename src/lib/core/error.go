@@ -2,7 +2,6 @@ package core
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/cloe-lang/cloe/src/lib/debug"
 )
@@ -51,16 +50,26 @@ func (e ErrorType) Name() string {
 	return e.name
 }
 
-// Lines returns multi-line string representation of an error which can be
-// printed as is to stdout or stderr.
-func (e ErrorType) Lines() string {
-	ss := make([]string, 0, len(e.callTrace))
+// Message returns a message of an error.
+func (e ErrorType) Message() string {
+	return e.message
+}
+
+// CallTrace returns a call trace of an error from outer frames to inner ones.
+func (e ErrorType) CallTrace() []*debug.Info {
+	is := make([]*debug.Info, 0, len(e.callTrace))
 
 	for i := range e.callTrace {
-		ss = append(ss, e.callTrace[len(e.callTrace)-1-i].Lines())
+		is = append(is, e.callTrace[len(e.callTrace)-1-i])
 	}
 
-	return strings.Join(ss, "") + e.name + ": " + e.message + "\n"
+	return is
+}
+
+// Lines returns multi-line string representation of an error which can be
+// printed as is to stdout or stderr.
+func (e ErrorType) Lines() string {
+	return debug.Render(e, debug.TextFormat)
 }
 
 // Error is implemented for error built-in interface.
@@ -142,6 +151,11 @@ func OutOfRangeError() *ErrorType {
 	return NewError("OutOfRangeError", "index is out of range")
 }
 
+// RecursionError creates an error value for evaluation of too deep recursion.
+func RecursionError() *ErrorType {
+	return NewError("RecursionError", "maximum recursion depth exceeded")
+}
+
 func notComparableError(v Value) *ErrorType {
 	return TypeError(v, "comparable value")
 }
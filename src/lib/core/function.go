@@ -2,6 +2,12 @@ package core
 
 import "github.com/cloe-lang/cloe/src/lib/systemt"
 
+// callable is an interface for values which can be called as functions.
+type callable interface {
+	Value
+	call(Arguments) Value
+}
+
 // FunctionType represents a function.
 type FunctionType func(Arguments) Value
 
@@ -59,7 +65,7 @@ var Partial = FunctionType(func(vars Arguments) Value {
 	return NewRawFunction(func(args Arguments) Value {
 		vars := vars
 		v := EvalPure(vars.nextPositional())
-		f, ok := v.(FunctionType)
+		f, ok := v.(callable)
 
 		if !ok {
 			return NotFunctionError(v)
@@ -1,9 +1,5 @@
 package core
 
-import (
-	"reflect"
-)
-
 // stringable is an interface for something convertable into StringType.
 // This should be implemented for all types including error type.
 type stringable interface {
@@ -73,6 +69,10 @@ func comparableID(v Value) byte {
 		return 4
 	case StringType:
 		return 5
+	case *RecordType:
+		return 6
+	case *CustomType:
+		return 7
 	}
 
 	panic("Unreachable")
@@ -123,22 +123,28 @@ var Compare = NewStrictFunction(
 	NewSignature([]string{"left", "right"}, "", nil, ""),
 	compareAsOrdered)
 
-func compareAsOrdered(vs ...Value) Value {
+func compareAsOrdered(vs ...Value) (result Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = r.(Value)
+		}
+	}()
+
 	v := EvalPure(vs[0])
-	o1, ok := v.(ordered)
+	o1, ok := asOrdered(v)
 
 	if !ok {
 		return NotOrderedError(v)
 	}
 
 	v = EvalPure(vs[1])
-	o2, ok := v.(ordered)
+	o2, ok := asOrdered(v)
 
 	if !ok {
 		return NotOrderedError(v)
 	}
 
-	if reflect.TypeOf(o1) != reflect.TypeOf(o2) {
+	if typeOf(o1) != typeOf(o2) {
 		s, err := EvalString(PApp(TypeOf, vs[1]))
 
 		if err != nil {
@@ -210,7 +216,18 @@ func isOrdered(vs ...Value) Value {
 
 		return True
 	default:
-		_, ok := x.(ordered)
+		_, ok := asOrdered(x)
 		return NewBoolean(ok)
 	}
 }
+
+// asOrdered converts a value into an ordered one. Custom values are ordered
+// only when they implement comparison.
+func asOrdered(v Value) (ordered, bool) {
+	if c, ok := v.(*CustomType); ok && !c.implements("compare") {
+		return nil, false
+	}
+
+	o, ok := v.(ordered)
+	return o, ok
+}
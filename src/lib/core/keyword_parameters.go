@@ -15,13 +15,17 @@ func (ks keywordParameters) arity() int {
 	return n
 }
 
-func (ks keywordParameters) bind(args *Arguments) []Value {
+func (ks keywordParameters) bind(args *Arguments) ([]Value, Value) {
 	vs := make([]Value, 0, ks.arity())
 
 	for _, o := range ks.parameters {
 		v := args.searchKeyword(o.name)
 
 		if v == nil {
+			if o.defaultValue == nil {
+				return nil, argumentError("missing keyword argument `%s`", o.name)
+			}
+
 			v = o.defaultValue
 		}
 
@@ -32,5 +36,5 @@ func (ks keywordParameters) bind(args *Arguments) []Value {
 		vs = append(vs, args.restKeywords())
 	}
 
-	return vs
+	return vs, nil
 }
@@ -10,3 +10,9 @@ type OptionalParameter struct {
 func NewOptionalParameter(n string, v Value) OptionalParameter {
 	return OptionalParameter{n, v}
 }
+
+// NewRequiredParameter creates a keyword parameter without a default value
+// whose argument must be passed by callers.
+func NewRequiredParameter(n string) OptionalParameter {
+	return OptionalParameter{n, nil}
+}
@@ -0,0 +1,196 @@
+package core
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// RecordType represents a record constructed by a constructor of a
+// user-defined type.
+type RecordType struct {
+	constructor *constructorType
+	fields      []Value
+}
+
+func (r *RecordType) eval() Value {
+	return r
+}
+
+func (r *RecordType) index(v Value) Value {
+	s, err := EvalString(v)
+
+	if err != nil {
+		return err
+	}
+
+	for i, f := range r.constructor.fields {
+		if f == string(s) {
+			return r.fields[i]
+		}
+	}
+
+	return NewError(
+		"KeyNotFoundError",
+		"field %s is not found in a record of %s",
+		s,
+		r.constructor.name)
+}
+
+func (r *RecordType) compare(x comparable) int {
+	rr := x.(*RecordType)
+
+	if c := strings.Compare(r.constructor.typ, rr.constructor.typ); c != 0 {
+		return c
+	} else if c := strings.Compare(r.constructor.name, rr.constructor.name); c != 0 {
+		return c
+	} else if c := compareConstructorIDs(r.constructor.id, rr.constructor.id); c != 0 {
+		return c
+	}
+
+	for i, v := range r.fields {
+		if c := compare(EvalPure(v), EvalPure(rr.fields[i])); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func (r *RecordType) string() Value {
+	ss := []string{r.constructor.name}
+
+	for _, v := range r.fields {
+		s, err := StrictDump(EvalPure(v))
+
+		if err != nil {
+			return err
+		}
+
+		ss = append(ss, string(s))
+	}
+
+	return NewString("(" + strings.Join(ss, " ") + ")")
+}
+
+func compareConstructorIDs(i, j uint64) int {
+	if i < j {
+		return -1
+	} else if i > j {
+		return 1
+	}
+
+	return 0
+}
+
+// constructorType represents a constructor of records. It is called as a
+// function. Constructors of the same names defined in different places are
+// distinguished by their IDs.
+type constructorType struct {
+	id        uint64
+	typ, name string
+	fields    []string
+	signature Signature
+}
+
+var constructorCount uint64
+
+func (c *constructorType) eval() Value {
+	return c
+}
+
+func (c *constructorType) call(args Arguments) Value {
+	vs, err := c.signature.Bind(args)
+
+	if err != nil {
+		return err
+	}
+
+	return &RecordType{c, vs}
+}
+
+func (c *constructorType) string() Value {
+	return NewString("<function>")
+}
+
+// Constructor creates a constructor of records from names of a type, the
+// constructor itself, and its fields.
+var Constructor = NewLazyFunction(
+	NewSignature([]string{"type", "name"}, "fields", nil, ""),
+	func(vs ...Value) Value {
+		t, err := EvalString(vs[0])
+
+		if err != nil {
+			return err
+		}
+
+		n, err := EvalString(vs[1])
+
+		if err != nil {
+			return err
+		}
+
+		l, err := EvalList(vs[2])
+
+		if err != nil {
+			return err
+		}
+
+		fs := []string{}
+
+		for !l.Empty() {
+			s, err := EvalString(l.First())
+
+			if err != nil {
+				return err
+			}
+
+			fs = append(fs, string(s))
+
+			if l, err = EvalList(l.Rest()); err != nil {
+				return err
+			}
+		}
+
+		return &constructorType{
+			atomic.AddUint64(&constructorCount, 1),
+			string(t),
+			string(n),
+			fs,
+			NewSignature(fs, "", nil, ""),
+		}
+	})
+
+// IsInstance checks if a value is a record constructed by a constructor.
+var IsInstance = NewLazyFunction(
+	NewSignature([]string{"record", "constructor"}, "", nil, ""),
+	func(vs ...Value) Value {
+		v := EvalPure(vs[1])
+		c, ok := v.(*constructorType)
+
+		if !ok {
+			return TypeError(v, "constructor")
+		}
+
+		switch x := EvalPure(vs[0]).(type) {
+		case *ErrorType:
+			return x
+		case *RecordType:
+			return NewBoolean(x.constructor == c)
+		}
+
+		return False
+	})
+
+// Fields returns a list of fields in a record.
+var Fields = NewLazyFunction(
+	NewSignature([]string{"record"}, "", nil, ""),
+	func(vs ...Value) Value {
+		v := EvalPure(vs[0])
+		r, ok := v.(*RecordType)
+
+		if !ok {
+			return TypeError(v, "record")
+		}
+
+		return NewList(r.fields...)
+	})
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testCircle = PApp(Constructor, NewString("shape"), NewString("circle"), NewString("radius"))
+
+var testRectangle = PApp(
+	Constructor,
+	NewString("shape"),
+	NewString("rectangle"),
+	NewString("width"),
+	NewString("height"))
+
+func TestConstructor(t *testing.T) {
+	r := EvalPure(PApp(testCircle, NewNumber(42)))
+
+	assert.Equal(t, "shape", string(EvalPure(PApp(TypeOf, r)).(StringType)))
+	assert.Equal(t, "function", string(EvalPure(PApp(TypeOf, testCircle)).(StringType)))
+	assert.Equal(t, NewNumber(42), EvalPure(PApp(Index, r, NewString("radius"))))
+}
+
+func TestConstructorError(t *testing.T) {
+	for _, v := range []Value{
+		PApp(Constructor, NewNumber(42), NewString("circle")),
+		PApp(Constructor, NewString("shape"), NewNumber(42)),
+		PApp(Constructor, NewString("shape"), NewString("circle"), NewNumber(42)),
+		PApp(testCircle),
+		PApp(Index, PApp(testCircle, NewNumber(42)), NewString("width")),
+		PApp(Index, PApp(testCircle, NewNumber(42)), NewNumber(1)),
+	} {
+		_, ok := EvalPure(v).(*ErrorType)
+		assert.True(t, ok)
+	}
+}
+
+func TestRecordEqual(t *testing.T) {
+	c := PApp(testCircle, NewNumber(1))
+
+	assert.True(t, testEqual(c, PApp(testCircle, NewNumber(1))))
+	assert.True(t, !testEqual(c, PApp(testCircle, NewNumber(2))))
+	assert.True(t, !testEqual(c, PApp(testRectangle, NewNumber(1), NewNumber(1))))
+	assert.True(t, !testEqual(c, NewDictionary([]KeyValue{{NewString("radius"), NewNumber(1)}})))
+}
+
+func TestRecordEqualWithConstructorsOfSameNames(t *testing.T) {
+	c := PApp(Constructor, NewString("shape"), NewString("circle"), NewString("radius"))
+
+	assert.True(t, !testEqual(PApp(c, NewNumber(1)), PApp(testCircle, NewNumber(1))))
+	assert.True(t, testLess(PApp(testCircle, NewNumber(1)), PApp(c, NewNumber(1))) !=
+		testLess(PApp(c, NewNumber(1)), PApp(testCircle, NewNumber(1))))
+	assert.Equal(t, False, EvalPure(PApp(IsInstance, PApp(c, NewNumber(1)), testCircle)))
+}
+
+func TestRecordDump(t *testing.T) {
+	for _, c := range []struct {
+		record Value
+		answer StringType
+	}{
+		{PApp(testCircle, NewNumber(42)), "(circle 42)"},
+		{PApp(testRectangle, NewString("foo"), NewList(Nil)), `(rectangle "foo" [nil])`},
+	} {
+		assert.Equal(t, c.answer, EvalPure(PApp(Dump, c.record)))
+	}
+
+	_, ok := EvalPure(PApp(Dump, PApp(testCircle, DummyError))).(*ErrorType)
+	assert.True(t, ok)
+}
+
+func TestIsInstance(t *testing.T) {
+	c := PApp(testCircle, NewNumber(42))
+
+	assert.Equal(t, True, EvalPure(PApp(IsInstance, c, testCircle)))
+	assert.Equal(t, False, EvalPure(PApp(IsInstance, c, testRectangle)))
+	assert.Equal(t, False, EvalPure(PApp(IsInstance, NewNumber(42), testCircle)))
+
+	for _, v := range []Value{
+		PApp(IsInstance, c, identity),
+		PApp(IsInstance, DummyError, testCircle),
+	} {
+		_, ok := EvalPure(v).(*ErrorType)
+		assert.True(t, ok)
+	}
+}
+
+func TestFields(t *testing.T) {
+	l := EvalPure(PApp(Fields, PApp(testRectangle, NewNumber(1), NewNumber(2))))
+	assert.True(t, testEqual(NewList(NewNumber(1), NewNumber(2)), l))
+
+	_, ok := EvalPure(PApp(Fields, NewNumber(42))).(*ErrorType)
+	assert.True(t, ok)
+}
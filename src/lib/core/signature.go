@@ -20,7 +20,11 @@ func (s Signature) Bind(args Arguments) ([]Value, Value) {
 		return nil, err
 	}
 
-	ks := s.keywords.bind(&args)
+	ks, err := s.keywords.bind(&args)
+
+	if err != nil {
+		return nil, err
+	}
 
 	if err := args.checkEmptyness(); err != nil {
 		return nil, err
@@ -29,6 +33,7 @@ func (s Signature) Bind(args Arguments) ([]Value, Value) {
 	return append(ps, ks...), nil
 }
 
-func (s Signature) arity() int {
+// Arity returns a number of values bound to parameters of a signature.
+func (s Signature) Arity() int {
 	return s.positionals.arity() + s.keywords.arity()
 }
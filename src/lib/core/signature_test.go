@@ -27,6 +27,14 @@ func TestSignatureBind(t *testing.T) {
 			NewSignature(nil, "", []OptionalParameter{NewOptionalParameter("foo", Nil)}, ""),
 			NewArguments(nil, []KeywordArgument{NewKeywordArgument("", NewDictionary([]KeyValue{{NewString("foo"), Nil}}))}),
 		},
+		{
+			NewSignature(nil, "", []OptionalParameter{NewRequiredParameter("x")}, ""),
+			NewArguments(nil, []KeywordArgument{NewKeywordArgument("x", Nil)}),
+		},
+		{
+			NewSignature(nil, "", []OptionalParameter{NewRequiredParameter("foo")}, ""),
+			NewArguments(nil, []KeywordArgument{NewKeywordArgument("", NewDictionary([]KeyValue{{NewString("foo"), Nil}}))}),
+		},
 		{
 			NewSignature(nil, "", nil, "foo"),
 			NewArguments(
@@ -35,7 +43,7 @@ func TestSignatureBind(t *testing.T) {
 		},
 	} {
 		vs, err := c.signature.Bind(c.arguments)
-		assert.Equal(t, c.signature.arity(), len(vs))
+		assert.Equal(t, c.signature.Arity(), len(vs))
 		assert.Equal(t, nil, err)
 	}
 }
@@ -62,3 +70,22 @@ func TestSignatureBindError(t *testing.T) {
 		assert.NotEqual(t, nil, err)
 	}
 }
+
+func TestSignatureBindWithMissingKeywordArgument(t *testing.T) {
+	s := NewSignature(
+		nil, "",
+		[]OptionalParameter{NewOptionalParameter("retries", Nil), NewRequiredParameter("timeout")}, "")
+
+	for _, ks := range [][]KeywordArgument{
+		nil,
+		{NewKeywordArgument("retries", Nil)},
+		{NewKeywordArgument("", NewDictionary([]KeyValue{{NewString("retries"), Nil}}))},
+	} {
+		_, err := s.Bind(NewArguments(nil, ks))
+		e, ok := err.(*ErrorType)
+
+		assert.True(t, ok)
+		assert.Equal(t, "ArgumentError", e.Name())
+		assert.Equal(t, "missing keyword argument `timeout`", e.Message())
+	}
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"math"
+	godebug "runtime/debug"
 	"sync"
 	"sync/atomic"
 
@@ -15,12 +17,26 @@ const (
 	spinLock
 )
 
+// stackDepth is a number of nested thunk evaluations on a goroutine.
+// Evaluation deeper than it continues on a new goroutine with a fresh stack.
+const stackDepth = 1 << 12
+
+// depthStackSize is an estimated size in bytes of Go stack frames used by a
+// nested thunk evaluation.
+const depthStackSize = 512
+
+// maxDepth is a maximum depth of nested thunk evaluations. It is based on the
+// maximum size of a goroutine stack so that recursion errors are raised only
+// where a single stack would overflow.
+var maxDepth = int32(maxStackSize() / depthStackSize)
+
 // Thunk you all!
 type Thunk struct {
 	result    Value
 	function  Value
 	args      Arguments
 	state     thunkState
+	depth     int32
 	blackHole sync.WaitGroup
 	info      *debug.Info
 }
@@ -51,31 +67,14 @@ func PApp(f Value, ps ...Value) *Thunk {
 // Eval evaluates a thunk and returns a pure or impure (effect) value.
 func (t *Thunk) eval() Value {
 	if t.lock(normal) {
-		for {
-			v := EvalPure(t.function)
+		if d := t.loadDepth(); d > maxDepth {
+			t.result = RecursionError()
 			t.function = nil
-
-			f, ok := v.(FunctionType)
-
-			if !ok {
-				t.result = NotFunctionError(v)
-				t.args = Arguments{}
-				break
-			}
-
-			t.result = f.call(t.args)
 			t.args = Arguments{}
-
-			child, ok := t.result.(*Thunk)
-
-			if !ok {
-				break
-			}
-
-			if !child.delegateEval(t) {
-				t.result = EvalPure(child)
-				break
-			}
+		} else if d%stackDepth == stackDepth-1 {
+			t.applyOnNewStack()
+		} else {
+			t.apply()
 		}
 
 		if e, ok := t.result.(*ErrorType); ok {
@@ -92,6 +91,78 @@ func (t *Thunk) eval() Value {
 	return t.result
 }
 
+// apply applies a function to arguments repeatedly while results are thunks
+// which can be delegated. Thunks evaluated in the application are one level
+// deeper than the thunk.
+func (t *Thunk) apply() {
+	d := t.loadDepth() + 1
+
+	for {
+		deepen(t.function, d)
+		v := EvalPure(t.function)
+		t.function = nil
+
+		f, ok := v.(callable)
+
+		if !ok {
+			t.result = NotFunctionError(v)
+			t.args = Arguments{}
+			break
+		}
+
+		t.args.deepen(d)
+		t.result = f.call(t.args)
+		t.args = Arguments{}
+
+		child, ok := t.result.(*Thunk)
+
+		if !ok {
+			break
+		}
+
+		if !child.delegateEval(t) {
+			deepen(child, d)
+			t.result = EvalPure(child)
+			break
+		}
+	}
+}
+
+// applyOnNewStack applies a function on a new goroutine to continue
+// evaluation with a fresh stack.
+func (t *Thunk) applyOnNewStack() {
+	done := make(chan struct{})
+
+	go func() {
+		t.apply()
+		close(done)
+	}()
+
+	<-done
+}
+
+// deepen sets a depth of a value's evaluation if it is a thunk deeper than
+// its current one.
+func deepen(v Value, d int32) {
+	t, ok := v.(*Thunk)
+
+	if !ok {
+		return
+	}
+
+	for {
+		if e := t.loadDepth(); e >= d || atomic.CompareAndSwapInt32(&t.depth, e, d) {
+			return
+		}
+	}
+}
+
+func maxStackSize() int {
+	s := godebug.SetMaxStack(math.MaxInt32)
+	godebug.SetMaxStack(s)
+	return s
+}
+
 func (t *Thunk) lock(s thunkState) bool {
 	for {
 		switch t.loadState() {
@@ -117,6 +188,10 @@ func (t *Thunk) delegateEval(parent *Thunk) bool {
 	return false
 }
 
+func (t *Thunk) loadDepth() int32 {
+	return atomic.LoadInt32(&t.depth)
+}
+
 func (t *Thunk) compareAndSwapState(old, new thunkState) bool {
 	return atomic.CompareAndSwapInt32((*int32)(&t.state), int32(old), int32(new))
 }
@@ -86,3 +86,49 @@ func BenchmarkMultiplePApp(b *testing.B) {
 				PApp(f, f, PApp(Rest, l))))
 	}
 }
+
+func TestThunkEvalWithDeepRecursion(t *testing.T) {
+	f := newRecursiveFunction()
+
+	e, ok := EvalPure(PApp(f, NewNumber(1e7))).(*ErrorType)
+
+	assert.True(t, ok)
+	assert.Equal(t, "RecursionError", e.Name())
+	assert.Equal(t, NewNumber(100), EvalPure(PApp(f, NewNumber(100))))
+	assert.Equal(t, NewNumber(1e6), EvalPure(PApp(f, NewNumber(1e6))))
+}
+
+func TestThunkEvalWithConcurrentDeepRecursion(t *testing.T) {
+	f := newRecursiveFunction()
+	n := 1e5
+	vs := make(chan Value)
+
+	for i := 0; i < 8; i++ {
+		go func() {
+			vs <- EvalPure(PApp(f, NewNumber(n)))
+		}()
+	}
+
+	for i := 0; i < 8; i++ {
+		assert.Equal(t, NewNumber(n), <-vs)
+	}
+}
+
+func newRecursiveFunction() Value {
+	f := Value(nil)
+	f = NewLazyFunction(
+		NewSignature([]string{"n"}, "", nil, ""),
+		func(vs ...Value) Value {
+			n, err := EvalNumber(vs[0])
+
+			if err != nil {
+				return err
+			} else if n == 0 {
+				return NewNumber(0)
+			}
+
+			return PApp(Add, NewNumber(1), PApp(f, NewNumber(float64(n)-1)))
+		})
+
+	return f
+}
@@ -67,7 +67,11 @@ func typeOf(vs ...Value) Value {
 		return NewString("number")
 	case StringType:
 		return NewString("string")
-	case FunctionType:
+	case *RecordType:
+		return NewString(v.constructor.typ)
+	case *CustomType:
+		return NewString(v.name)
+	case callable:
 		return NewString("function")
 	case *ErrorType:
 		return v
@@ -71,6 +71,16 @@ func evalCollection(v Value) (collection, Value) {
 	return c, nil
 }
 
+func evalIndexable(v Value) (indexable, Value) {
+	i, ok := EvalPure(v).(indexable)
+
+	if !ok {
+		return nil, NotCollectionError(v)
+	}
+
+	return i, nil
+}
+
 // EvalPure evaluates a pure value.
 func EvalPure(v Value) Value {
 	v = v.eval()
@@ -2,3 +2,6 @@ package debug
 
 // Debug is a debug mode flag for an entire program.
 var Debug = false
+
+// ErrorFormat is a format of errors printed for an entire program.
+var ErrorFormat = TextFormat
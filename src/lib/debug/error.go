@@ -0,0 +1,71 @@
+package debug
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error represents an error found at some locations in source code.
+type Error struct {
+	trace         []*Info
+	name, message string
+}
+
+// NewError creates an error from its location, name, and formatted message.
+func NewError(i *Info, n, m string, xs ...interface{}) *Error {
+	if i == nil {
+		return NewTracedError(nil, n, m, xs...)
+	}
+
+	return NewTracedError([]*Info{i}, n, m, xs...)
+}
+
+// NewTracedError creates an error from its locations ordered from outer ones
+// to inner ones, name, and formatted message.
+func NewTracedError(is []*Info, n, m string, xs ...interface{}) *Error {
+	return &Error{is, n, fmt.Sprintf(m, xs...)}
+}
+
+// Info returns debug information of the innermost location where an error is
+// found.
+func (e *Error) Info() *Info {
+	if len(e.trace) == 0 {
+		return nil
+	}
+
+	return e.trace[len(e.trace)-1]
+}
+
+// Name returns a name of an error.
+func (e *Error) Name() string {
+	return e.name
+}
+
+// Message returns a message of an error.
+func (e *Error) Message() string {
+	return e.message
+}
+
+// CallTrace returns locations where an error is found as a call trace.
+func (e *Error) CallTrace() []*Info {
+	return e.trace
+}
+
+// Error is implemented for error built-in interface.
+func (e *Error) Error() string {
+	return renderText(e, false)
+}
+
+// Errors represents multiple errors found in source code.
+type Errors []error
+
+// Error is implemented for error built-in interface.
+func (es Errors) Error() string {
+	ss := make([]string, 0, len(es))
+
+	for _, e := range es {
+		ss = append(ss, strings.TrimSpace(e.Error())+"\n")
+	}
+
+	return strings.Join(ss, "")
+}
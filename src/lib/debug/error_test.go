@@ -0,0 +1,40 @@
+package debug
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	i := NewInfo("foo.cloe", 1, 2, "(print x)")
+	e := NewError(i, "NameError", "the name, %s is not found", "x")
+
+	assert.Equal(t, i, e.Info())
+	assert.Equal(t, "NameError", e.Name())
+	assert.Equal(t, "the name, x is not found", e.Message())
+	assert.Equal(t, "foo.cloe:1:2:\t(print x)\n             \t ^\nNameError: the name, x is not found\n", e.Error())
+}
+
+func TestErrors(t *testing.T) {
+	es := Errors{NewError(nil, "FooError", "foo"), errors.New("bar\n")}
+	assert.Equal(t, "FooError: foo\nbar\n", es.Error())
+}
+
+func TestErrorCallTrace(t *testing.T) {
+	i := NewInfo("foo.cloe", 1, 2, "(print x)")
+
+	assert.Equal(t, []*Info{i}, NewError(i, "FooError", "foo").CallTrace())
+	assert.Equal(t, 0, len(NewError(nil, "FooError", "foo").CallTrace()))
+}
+
+func TestTracedError(t *testing.T) {
+	i := NewInfo("foo.cloe", 1, 1, `(import "./bar")`)
+	j := NewInfo("bar.cloe", 1, 1, `(import "./foo")`)
+	e := NewTracedError([]*Info{i, j}, "ImportError", "foo")
+
+	assert.Equal(t, j, e.Info())
+	assert.Equal(t, []*Info{i, j}, e.CallTrace())
+	assert.Nil(t, NewTracedError(nil, "FooError", "foo").Info())
+}
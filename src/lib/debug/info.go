@@ -39,11 +39,65 @@ func (i *Info) Lines() string {
 		return ""
 	}
 
+	return i.location() + "\t" + i.source + "\n"
+}
+
+func (i *Info) location() string {
 	p := "NA"
 
 	if i.linePosition > 0 {
 		p = fmt.Sprint(i.linePosition)
 	}
 
-	return fmt.Sprintf("%s:%d:%s:\t%s\n", i.file, i.lineNumber, p, i.source)
+	return fmt.Sprintf("%s:%d:%s:", i.file, i.lineNumber, p)
+}
+
+// caret returns a caret under a position in source code. Tabs in the source
+// code are kept for alignment.
+func (i *Info) caret() string {
+	rs := []rune(i.source)
+
+	if i.linePosition <= 0 || i.linePosition > len(rs)+1 {
+		return ""
+	}
+
+	ss := make([]rune, 0, i.linePosition)
+
+	for _, r := range rs[:i.linePosition-1] {
+		if r != '\t' {
+			r = ' '
+		}
+
+		ss = append(ss, r)
+	}
+
+	return string(append(ss, '^'))
+}
+
+func (i *Info) equal(j *Info) bool {
+	if i == nil || j == nil {
+		return i == j
+	}
+
+	return *i == *j
+}
+
+// File returns a file name of a location.
+func (i *Info) File() string {
+	return i.file
+}
+
+// LineNumber returns a line number of a location.
+func (i *Info) LineNumber() int {
+	return i.lineNumber
+}
+
+// LinePosition returns a position in a line of a location.
+func (i *Info) LinePosition() int {
+	return i.linePosition
+}
+
+// Source returns source code at a line of a location.
+func (i *Info) Source() string {
+	return i.source
 }
@@ -0,0 +1,139 @@
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Format is a format of errors rendered for users.
+type Format int
+
+// Formats of rendered errors.
+const (
+	TextFormat Format = iota
+	ColoredTextFormat
+	JSONFormat
+)
+
+const (
+	boldColor  = "\x1b[1m"
+	redColor   = "\x1b[1;31m"
+	faintColor = "\x1b[2m"
+	resetColor = "\x1b[0m"
+)
+
+// TracedError is an error with a call trace ordered from outer frames to
+// inner ones.
+type TracedError interface {
+	error
+	Name() string
+	Message() string
+	CallTrace() []*Info
+}
+
+// Render renders an error in a format.
+func Render(err error, f Format) string {
+	switch err := err.(type) {
+	case Errors:
+		ss := make([]string, 0, len(err))
+
+		for _, e := range err {
+			ss = append(ss, strings.TrimSpace(Render(e, f))+"\n")
+		}
+
+		return strings.Join(ss, "")
+	case TracedError:
+		if f == JSONFormat {
+			return renderJSON(err.Name(), err.Message(), err.CallTrace())
+		}
+
+		return renderText(err, f == ColoredTextFormat)
+	}
+
+	if f == JSONFormat {
+		return renderJSON("", strings.TrimSpace(err.Error()), nil)
+	}
+
+	return err.Error()
+}
+
+// renderText renders an error with a caret under each location in its call
+// trace. Repeated frames are collapsed into one.
+func renderText(e TracedError, colored bool) string {
+	color := func(c, s string) string {
+		if colored {
+			return c + s + resetColor
+		}
+
+		return s
+	}
+
+	ss := []string{}
+	is := e.CallTrace()
+
+	for n := 0; n < len(is); n += collapse(is[n:]) {
+		i := is[n]
+
+		if i == nil {
+			continue
+		}
+
+		l := i.location()
+		ss = append(ss, color(boldColor, l)+"\t"+i.source+"\n")
+
+		if c := i.caret(); c != "" {
+			ss = append(ss, strings.Repeat(" ", len([]rune(l)))+"\t"+color(redColor, c)+"\n")
+		}
+
+		if m := collapse(is[n:]) - 1; m > 0 {
+			ss = append(ss, color(faintColor, fmt.Sprintf("\t(previous frame repeated %d times)", m))+"\n")
+		}
+	}
+
+	return strings.Join(ss, "") + color(redColor, e.Name()) + ": " + e.Message() + "\n"
+}
+
+// collapse returns a number of the first frames identical in a call trace.
+func collapse(is []*Info) int {
+	n := 1
+
+	for n < len(is) && is[n].equal(is[0]) {
+		n++
+	}
+
+	return n
+}
+
+type jsonError struct {
+	Name    string      `json:"name,omitempty"`
+	Message string      `json:"message"`
+	Trace   []jsonFrame `json:"trace"`
+}
+
+type jsonFrame struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Source   string `json:"source"`
+	Repeated int    `json:"repeated,omitempty"`
+}
+
+// renderJSON renders an error as a line of a JSON object.
+func renderJSON(name, message string, is []*Info) string {
+	fs := []jsonFrame{}
+
+	for n := 0; n < len(is); n += collapse(is[n:]) {
+		if i := is[n]; i != nil {
+			fs = append(fs, jsonFrame{i.file, i.lineNumber, i.linePosition, i.source, collapse(is[n:]) - 1})
+		}
+	}
+
+	bs, err := json.Marshal(jsonError{name, message, fs})
+
+	if err != nil {
+		panic(err)
+	}
+
+	return string(bs) + "\n"
+}
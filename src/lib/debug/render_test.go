@@ -0,0 +1,92 @@
+package debug
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTracedError struct {
+	name, message string
+	trace         []*Info
+}
+
+func (e testTracedError) Error() string {
+	return renderText(e, false)
+}
+
+func (e testTracedError) Name() string {
+	return e.name
+}
+
+func (e testTracedError) Message() string {
+	return e.message
+}
+
+func (e testTracedError) CallTrace() []*Info {
+	return e.trace
+}
+
+func TestRender(t *testing.T) {
+	i := NewInfo("foo.cloe", 2, 3, "\t(f x)")
+	j := NewInfo("foo.cloe", 1, 1, "(def (f x) (g x))")
+	e := testTracedError{"FooError", "foo", []*Info{i, j, j, j}}
+
+	assert.Equal(
+		t,
+		strings.Join([]string{
+			"foo.cloe:2:3:\t\t(f x)",
+			"             \t\t ^",
+			"foo.cloe:1:1:\t(def (f x) (g x))",
+			"             \t^",
+			"\t(previous frame repeated 2 times)",
+			"FooError: foo",
+			"",
+		}, "\n"),
+		Render(e, TextFormat))
+}
+
+func TestRenderWithColors(t *testing.T) {
+	s := Render(NewError(NewInfo("foo.cloe", 1, 1, "(f x)"), "FooError", "foo"), ColoredTextFormat)
+
+	assert.True(t, strings.Contains(s, redColor+"FooError"+resetColor))
+	assert.True(t, strings.Contains(s, boldColor+"foo.cloe:1:1:"+resetColor))
+}
+
+func TestRenderJSON(t *testing.T) {
+	i := NewInfo("foo.cloe", 1, 2, "(f x)")
+	e := testTracedError{"FooError", "foo", []*Info{i, i, nil}}
+
+	s := Render(e, JSONFormat)
+	assert.Equal(t, 1, strings.Count(s, "\n"))
+
+	x := jsonError{}
+	assert.Nil(t, json.Unmarshal([]byte(s), &x))
+	assert.Equal(t, jsonError{"FooError", "foo", []jsonFrame{{"foo.cloe", 1, 2, "(f x)", 1}}}, x)
+}
+
+func TestRenderErrors(t *testing.T) {
+	es := Errors{NewError(nil, "FooError", "foo"), errors.New("bar")}
+
+	assert.Equal(t, "FooError: foo\nbar\n", Render(es, TextFormat))
+	assert.Equal(t, `{"name":"FooError","message":"foo","trace":[]}`+"\n"+`{"message":"bar","trace":[]}`+"\n", Render(es, JSONFormat))
+}
+
+func TestInfoCaret(t *testing.T) {
+	for _, c := range []struct {
+		info  *Info
+		caret string
+	}{
+		{NewInfo("foo.cloe", 1, 1, "(f x)"), "^"},
+		{NewInfo("foo.cloe", 1, 4, "(f x)"), "   ^"},
+		{NewInfo("foo.cloe", 1, 3, "\t(f x)"), "\t ^"},
+		{NewInfo("foo.cloe", 1, -1, ""), ""},
+		{NewInfo("foo.cloe", 1, 6, "(f x)"), "     ^"},
+		{NewInfo("foo.cloe", 1, 10, "(f x)"), ""},
+	} {
+		assert.Equal(t, c.caret, c.info.caret())
+	}
+}
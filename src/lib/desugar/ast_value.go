@@ -0,0 +1,387 @@
+package desugar
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// ASTs are represented as values passed to and returned from macros. Names
+// and literals are strings of their source code, applications are lists of
+// functions and arguments in which `.` and `..` are strings as written in
+// source code, anonymous functions are dictionaries of "parameters" and
+// "body", and match expressions are dictionaries of "value" and "cases" in
+// which `|` is a string as written in source code before guards.
+
+const (
+	parametersKey = "parameters"
+	bodyKey       = "body"
+	valueKey      = "value"
+	casesKey      = "cases"
+	keywordsToken = "."
+	expandedToken = ".."
+	guardToken    = "|"
+	requiredToken = "!"
+)
+
+// quote converts an expression into a value.
+func quote(x interface{}) core.Value {
+	switch x := x.(type) {
+	case string:
+		return core.NewString(x)
+	case ast.App:
+		vs := []core.Value{quote(x.Function())}
+
+		for _, p := range x.Arguments().Positionals() {
+			if p.Expanded() {
+				vs = append(vs, core.NewString(expandedToken))
+			}
+
+			vs = append(vs, quote(p.Value()))
+		}
+
+		if len(x.Arguments().Keywords()) != 0 {
+			vs = append(vs, core.NewString(keywordsToken))
+		}
+
+		for _, k := range x.Arguments().Keywords() {
+			if k.Name() == "" {
+				vs = append(vs, core.NewString(expandedToken))
+			} else {
+				vs = append(vs, core.NewString(k.Name()))
+			}
+
+			vs = append(vs, quote(k.Value()))
+		}
+
+		return core.NewList(vs...)
+	case ast.AnonymousFunction:
+		return core.NewDictionary([]core.KeyValue{
+			{Key: core.NewString(parametersKey), Value: quoteSignature(x.Signature())},
+			{Key: core.NewString(bodyKey), Value: quote(x.Body())},
+		})
+	case ast.Match:
+		vs := make([]core.Value, 0, 2*len(x.Cases()))
+
+		for _, c := range x.Cases() {
+			vs = append(vs, quote(c.Pattern()))
+
+			if g := c.Guard(); g != nil {
+				vs = append(vs, core.NewString(guardToken), quote(g))
+			}
+
+			vs = append(vs, quote(c.Value()))
+		}
+
+		return core.NewDictionary([]core.KeyValue{
+			{Key: core.NewString(valueKey), Value: quote(x.Value())},
+			{Key: core.NewString(casesKey), Value: core.NewList(vs...)},
+		})
+	}
+
+	panic(fmt.Errorf("Invalid value: %#v", x))
+}
+
+func quoteSignature(s ast.Signature) core.Value {
+	vs := []core.Value{}
+
+	for _, p := range s.Positionals() {
+		vs = append(vs, core.NewString(p))
+	}
+
+	if r := s.RestPositionals(); r != "" {
+		vs = append(vs, core.NewString(expandedToken), core.NewString(r))
+	}
+
+	if len(s.Keywords()) != 0 || s.RestKeywords() != "" {
+		vs = append(vs, core.NewString(keywordsToken))
+	}
+
+	for _, k := range s.Keywords() {
+		if k.Required() {
+			vs = append(vs, core.NewString(requiredToken), core.NewString(k.Name()))
+		} else {
+			vs = append(vs, core.NewString(k.Name()), quote(k.DefaultValue()))
+		}
+	}
+
+	if r := s.RestKeywords(); r != "" {
+		vs = append(vs, core.NewString(expandedToken), core.NewString(r))
+	}
+
+	return core.NewList(vs...)
+}
+
+// unquote converts a value into an expression. Applications in it have debug
+// information of a macro call.
+func unquote(v core.Value, i *debug.Info) (interface{}, error) {
+	switch x := core.EvalPure(v).(type) {
+	case core.StringType:
+		return string(x), nil
+	case *core.NumberType:
+		return strconv.FormatFloat(float64(*x), 'g', -1, 64), nil
+	case *core.ListType:
+		vs, err := listToSlice(x, i)
+
+		if err != nil {
+			return nil, err
+		} else if len(vs) == 0 {
+			return nil, invalidASTError(i, "applications must have functions")
+		}
+
+		f, err := unquote(vs[0], i)
+
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := unquoteArguments(vs[1:], i)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return ast.NewApp(f, args, i), nil
+	case *core.DictionaryType:
+		if p, ok := x.Search(core.NewString(parametersKey)); ok {
+			return unquoteAnonymousFunction(p, x, i)
+		} else if cs, ok := x.Search(core.NewString(casesKey)); ok {
+			return unquoteMatch(cs, x, i)
+		}
+	case *core.ErrorType:
+		return nil, debug.NewError(i, x.Name(), "%s", x.Message())
+	}
+
+	s, e := core.StrictDump(v)
+
+	if e != nil {
+		return nil, valueError(e, i)
+	}
+
+	return nil, invalidASTError(i, "%s is not an AST", s)
+}
+
+func unquoteArguments(vs []core.Value, i *debug.Info) (ast.Arguments, error) {
+	ps := []ast.PositionalArgument{}
+	ks := []ast.KeywordArgument{}
+	keyword := false
+
+	for n := 0; n < len(vs); n++ {
+		x, err := unquote(vs[n], i)
+
+		if err != nil {
+			return ast.Arguments{}, err
+		} else if x == keywordsToken && !keyword {
+			keyword = true
+			continue
+		}
+
+		expanded := x == expandedToken
+
+		if expanded || keyword {
+			if n++; n == len(vs) {
+				return ast.Arguments{}, invalidASTError(i, "%s must be followed by an expression", x)
+			}
+
+			y, err := unquote(vs[n], i)
+
+			if err != nil {
+				return ast.Arguments{}, err
+			}
+
+			if keyword {
+				s, ok := x.(string)
+
+				if !ok {
+					return ast.Arguments{}, invalidASTError(i, "keyword arguments must have names")
+				} else if expanded {
+					s = ""
+				}
+
+				ks = append(ks, ast.NewKeywordArgument(s, y))
+				continue
+			}
+
+			x = y
+		}
+
+		ps = append(ps, ast.NewPositionalArgument(x, expanded))
+	}
+
+	return ast.NewArguments(ps, ks), nil
+}
+
+func unquoteAnonymousFunction(p core.Value, d *core.DictionaryType, i *debug.Info) (interface{}, error) {
+	l, e := core.EvalList(p)
+
+	if e != nil {
+		return nil, valueError(e, i)
+	}
+
+	vs, err := listToSlice(l, i)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := unquoteSignature(vs, i)
+
+	if err != nil {
+		return nil, err
+	}
+
+	b, ok := d.Search(core.NewString(bodyKey))
+
+	if !ok {
+		return nil, invalidASTError(i, "anonymous functions must have bodies")
+	}
+
+	x, err := unquote(b, i)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewAnonymousFunction(s, x), nil
+}
+
+func unquoteSignature(vs []core.Value, i *debug.Info) (ast.Signature, error) {
+	ps, ks := []string{}, []ast.OptionalParameter{}
+	pr, kr := "", ""
+	keyword := false
+
+	for n := 0; n < len(vs); n++ {
+		x, err := unquote(vs[n], i)
+
+		if err != nil {
+			return ast.Signature{}, err
+		}
+
+		s, ok := x.(string)
+
+		if !ok {
+			return ast.Signature{}, invalidASTError(i, "parameters must be names")
+		} else if s == keywordsToken && !keyword {
+			keyword = true
+			continue
+		} else if s != expandedToken && !keyword {
+			ps = append(ps, s)
+			continue
+		} else if n++; n == len(vs) {
+			return ast.Signature{}, invalidASTError(i, "%s must be followed by a name", s)
+		}
+
+		y, err := unquote(vs[n], i)
+
+		if err != nil {
+			return ast.Signature{}, err
+		}
+
+		t, ok := y.(string)
+
+		switch {
+		case s != expandedToken && s != requiredToken:
+			ks = append(ks, ast.NewOptionalParameter(s, y))
+		case !ok:
+			return ast.Signature{}, invalidASTError(i, "parameters must be names")
+		case s == requiredToken:
+			ks = append(ks, ast.NewRequiredParameter(t))
+		case keyword:
+			kr = t
+		default:
+			pr = t
+		}
+	}
+
+	return ast.NewSignature(ps, pr, ks, kr), nil
+}
+
+func unquoteMatch(cs core.Value, d *core.DictionaryType, i *debug.Info) (interface{}, error) {
+	v, ok := d.Search(core.NewString(valueKey))
+
+	if !ok {
+		return nil, invalidASTError(i, "match expressions must have values")
+	}
+
+	x, err := unquote(v, i)
+
+	if err != nil {
+		return nil, err
+	}
+
+	l, e := core.EvalList(cs)
+
+	if e != nil {
+		return nil, valueError(e, i)
+	}
+
+	vs, err := listToSlice(l, i)
+
+	if err != nil {
+		return nil, err
+	}
+
+	xs := make([]interface{}, 0, len(vs))
+
+	for _, v := range vs {
+		x, err := unquote(v, i)
+
+		if err != nil {
+			return nil, err
+		}
+
+		xs = append(xs, x)
+	}
+
+	ys := []ast.MatchCase{}
+
+	for len(xs) != 0 {
+		p, g := xs[0], interface{}(nil)
+
+		if len(xs) > 3 && xs[1] == guardToken {
+			g, xs = xs[2], xs[2:]
+		}
+
+		if len(xs) < 2 {
+			return nil, invalidASTError(i, "cases of match expressions must be pairs of patterns and values")
+		}
+
+		ys = append(ys, ast.NewGuardedMatchCase(p, g, xs[1]))
+		xs = xs[2:]
+	}
+
+	if len(ys) == 0 {
+		return nil, invalidASTError(i, "cases of match expressions must be pairs of patterns and values")
+	}
+
+	return ast.NewMatch(x, ys), nil
+}
+
+func listToSlice(l *core.ListType, i *debug.Info) ([]core.Value, error) {
+	vs := []core.Value{}
+
+	for !l.Empty() {
+		vs = append(vs, l.First())
+
+		var e core.Value
+
+		if l, e = core.EvalList(l.Rest()); e != nil {
+			return nil, valueError(e, i)
+		}
+	}
+
+	return vs, nil
+}
+
+// valueError converts an error value into an error at a macro call.
+func valueError(v core.Value, i *debug.Info) error {
+	_, err := unquote(v, i)
+	return err
+}
+
+func invalidASTError(i *debug.Info, m string, xs ...interface{}) error {
+	return debug.NewError(i, "MacroError", m, xs...)
+}
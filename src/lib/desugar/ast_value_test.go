@@ -0,0 +1,95 @@
+package desugar
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteAndUnquote(t *testing.T) {
+	for _, s := range []string{
+		`x`,
+		`"foo"`,
+		`(f x)`,
+		`(f x ..xs . y 42 ..ys)`,
+		`[1 2 ..xs]`,
+		`{"foo" 42}`,
+		`(\ (x ..xs . y 1 ..ys) (f x y))`,
+		`(\ (x . !y z 1) (f x y z))`,
+		`(\ () 42)`,
+		`(match x [] 0 [y ..ys] y _ 42)`,
+		`(match x [y ..ys] | (> y 0) y (or 1 2) 3 _ | z 42)`,
+	} {
+		m, err := parse.MainModule("<test>", s)
+		assert.Nil(t, err)
+
+		v := quote(m[0].(interface{ Expr() interface{} }).Expr())
+		x, err := unquote(v, nil)
+		assert.Nil(t, err)
+
+		s1, e := core.StrictDump(v)
+		assert.Nil(t, e)
+		s2, e := core.StrictDump(quote(x))
+		assert.Nil(t, e)
+		assert.Equal(t, s1, s2)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	m, err := parse.MainModule("<test>", `(f x ..xs . y 42)`)
+	assert.Nil(t, err)
+
+	s, e := core.StrictDump(quote(m[0].(interface{ Expr() interface{} }).Expr()))
+
+	assert.Nil(t, e)
+	assert.Equal(t, core.NewString(`["f" "x" ".." "xs" "." "y" "42"]`), s)
+}
+
+func TestUnquoteNumber(t *testing.T) {
+	x, err := unquote(core.NewNumber(42), nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "42", x)
+}
+
+func TestUnquoteError(t *testing.T) {
+	for _, v := range []core.Value{
+		core.Nil,
+		core.True,
+		core.EmptyList,
+		core.EmptyDictionary,
+		core.NewList(core.NewString("f"), core.NewString("..")),
+		core.NewList(core.NewString("f"), core.NewString("."), core.NewString("x")),
+		core.NewList(core.NewString("f"), core.NewString("."), core.EmptyList, core.NewString("x")),
+		core.NewDictionary([]core.KeyValue{{Key: core.NewString("parameters"), Value: core.EmptyList}}),
+		core.NewDictionary([]core.KeyValue{{Key: core.NewString("parameters"), Value: core.Nil}}),
+		core.NewDictionary([]core.KeyValue{
+			{Key: core.NewString("parameters"), Value: core.NewList(core.NewString(".."))},
+			{Key: core.NewString("body"), Value: core.NewString("x")},
+		}),
+		core.NewDictionary([]core.KeyValue{
+			{Key: core.NewString("parameters"), Value: core.NewList(core.Nil)},
+			{Key: core.NewString("body"), Value: core.NewString("x")},
+		}),
+		core.NewDictionary([]core.KeyValue{{Key: core.NewString("cases"), Value: core.EmptyList}}),
+		core.NewDictionary([]core.KeyValue{
+			{Key: core.NewString("value"), Value: core.NewString("x")},
+			{Key: core.NewString("cases"), Value: core.NewList(core.NewString("x"))},
+		}),
+		core.NewDictionary([]core.KeyValue{
+			{Key: core.NewString("value"), Value: core.NewString("x")},
+			{Key: core.NewString("cases"), Value: core.NewList(
+				core.NewString("x"), core.NewString("|"), core.NewString("y"))},
+		}),
+		core.NewList(core.NewString("f"), core.NewError("MyError", "")),
+		core.PApp(core.Prepend, core.NewString("f"), core.Nil),
+	} {
+		_, err := unquote(v, debug.NewGoInfo(0))
+
+		_, ok := err.(*debug.Error)
+		assert.True(t, ok)
+	}
+}
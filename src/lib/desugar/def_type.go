@@ -0,0 +1,34 @@
+package desugar
+
+import (
+	"strconv"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+)
+
+// desugarDefType converts a type definition into definitions of variables of
+// its constructors.
+func desugarDefType(x interface{}) []interface{} {
+	t, ok := x.(ast.DefType)
+
+	if !ok {
+		return []interface{}{x}
+	}
+
+	ls := make([]interface{}, 0, len(t.Constructors()))
+
+	for _, c := range t.Constructors() {
+		args := []interface{}{strconv.Quote(t.Name()), strconv.Quote(c.Name())}
+
+		for _, f := range c.Fields() {
+			args = append(args, strconv.Quote(f))
+		}
+
+		ls = append(ls, ast.NewLetVarWithInfo(
+			c.Name(),
+			ast.NewPApp("$constructor", args, t.DebugInfo()),
+			t.DebugInfo()))
+	}
+
+	return ls
+}
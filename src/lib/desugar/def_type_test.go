@@ -0,0 +1,32 @@
+package desugar
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDesugarDefType(t *testing.T) {
+	ss := desugarDefType(ast.NewDefType(
+		"shape",
+		[]ast.Constructor{
+			ast.NewConstructor("circle", []string{"radius"}),
+			ast.NewConstructor("rectangle", []string{"width", "height"}),
+		},
+		debug.NewGoInfo(0)))
+
+	assert.Equal(t, 2, len(ss))
+	assert.Equal(t, "(let circle ($constructor \"shape\" \"circle\" \"radius\"))\n", fmt.Sprint(ss[0]))
+	assert.Equal(
+		t,
+		"(let rectangle ($constructor \"shape\" \"rectangle\" \"width\" \"height\"))\n",
+		fmt.Sprint(ss[1]))
+}
+
+func TestDesugarDefTypeWithOtherStatements(t *testing.T) {
+	x := ast.NewLetVarWithInfo("x", "42", debug.NewGoInfo(0))
+	assert.Equal(t, []interface{}{x}, desugarDefType(x))
+}
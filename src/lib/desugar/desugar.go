@@ -1,23 +1,67 @@
 package desugar
 
 import (
+	"github.com/cloe-lang/cloe/src/lib/debug"
 	"github.com/cloe-lang/cloe/src/lib/desugar/match"
 )
 
 // Desugar desugars a module of statements in AST.
 func Desugar(ss []interface{}) []interface{} {
-	for _, f := range []func(interface{}) []interface{}{
-		desugarLetMatch,
-		desugarEmptyCollection,
-		desugarDictionaryExpansion,
-		desugarAnonymousFunctions,
-		match.Desugar,
+	ss, err := DesugarWithMacros(ss, nil)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ss
+}
+
+// DesugarWithMacros desugars a module of statements in AST expanding macros.
+// Macro definitions are removed from the module.
+func DesugarWithMacros(ss []interface{}, ms Macros) ([]interface{}, error) {
+	ss, err := DesugarSyntax(ss, ms)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return desugar(
+		ss,
 		desugarMutualRecursionStatement,
 		desugarSelfRecursiveStatement,
 		flattenStatement,
 		removeUnusedVariables,
-		removeAliases,
-	} {
+		removeAliases), nil
+}
+
+// DesugarSyntax desugars syntax sugar and expands macros in a module of
+// statements. Functions and variables in its result are still bound in
+// lexical scopes as written in source code.
+func DesugarSyntax(ss []interface{}, ms Macros) ([]interface{}, error) {
+	e := newMacroExpander(ms)
+
+	ss = desugar(
+		ss,
+		desugarDefType,
+		desugarLetMatch,
+		desugarThread,
+		desugarInterpolation,
+		e.expandStatement,
+		desugarEmptyCollection,
+		desugarDictionaryExpansion,
+		desugarAnonymousFunctions)
+
+	if len(e.errors) != 0 {
+		return nil, debug.Errors(e.errors)
+	} else if err := match.CheckOrPatterns(ss); err != nil {
+		return nil, err
+	}
+
+	return desugar(ss, match.Desugar), nil
+}
+
+func desugar(ss []interface{}, fs ...func(interface{}) []interface{}) []interface{} {
+	for _, f := range fs {
 		new := make([]interface{}, 0, 2*len(ss))
 
 		for _, s := range ss {
@@ -6,6 +6,7 @@ import (
 	"github.com/cloe-lang/cloe/src/lib/ast"
 	"github.com/cloe-lang/cloe/src/lib/consts"
 	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/parse"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -139,3 +140,46 @@ func TestDesugar(t *testing.T) {
 		}
 	}
 }
+
+func TestDesugarSyntax(t *testing.T) {
+	m, err := parse.MainModule("<test>", `(def (f x:number) :string (match x 0 "zero" _ ((\ (y:number) (toString y)) x)))`)
+	assert.Nil(t, err)
+
+	ss, err := DesugarSyntax(m, nil)
+	assert.Nil(t, err)
+
+	f := ss[0].(ast.DefFunction)
+	assert.Equal(t, "number", f.Signature().Type("x").String())
+	assert.Equal(t, "string", f.Signature().Result().String())
+
+	n := 0
+
+	for _, l := range f.Lets() {
+		if g, ok := l.(ast.DefFunction); ok && g.Signature().Type("y") != nil {
+			n++
+		}
+	}
+
+	assert.Equal(t, 1, n)
+
+	ast.Convert(func(x interface{}) interface{} {
+		switch x.(type) {
+		case ast.AnonymousFunction, ast.Match:
+			t.Errorf("syntax sugar is left: %#v", x)
+		}
+
+		return nil
+	}, f)
+}
+
+func TestDesugarSyntaxWithInvalidOrPatterns(t *testing.T) {
+	m, err := parse.MainModule("<test>", "(let x 1)\n(print (match x\n  (or [a] b) a))")
+	assert.Nil(t, err)
+
+	_, err = DesugarSyntax(m, nil)
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(es))
+	assert.Equal(t, 3, es[0].(*debug.Error).Info().LineNumber())
+}
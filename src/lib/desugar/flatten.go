@@ -64,12 +64,13 @@ func letFlattenedFunction(f ast.DefFunction, n string, args []string) ast.DefFun
 }
 
 func letClosure(f ast.DefFunction, n string, args []string) ast.LetVar {
-	return ast.NewLetVar(
+	return ast.NewLetVarWithInfo(
 		f.Name(),
 		ast.NewApp(
 			"$partial",
 			ast.NewArguments(namesToPosArgs(append([]string{n}, args...)), nil),
-			f.DebugInfo()))
+			f.DebugInfo()),
+		f.DebugInfo())
 }
 
 func namesToPosArgs(ns []string) []ast.PositionalArgument {
@@ -0,0 +1,41 @@
+package desugar
+
+import (
+	"github.com/cloe-lang/cloe/src/lib/ast"
+)
+
+func desugarInterpolation(x interface{}) []interface{} {
+	return []interface{}{ast.Convert(interpolationToMerge, x)}
+}
+
+// interpolationToMerge converts an interpolated string literal into an
+// application of merge to strings and expressions converted into strings.
+func interpolationToMerge(x interface{}) interface{} {
+	i, ok := x.(ast.Interpolation)
+
+	if !ok {
+		return nil
+	}
+
+	args := make([]interface{}, 0, 2*len(i.Strings()))
+
+	for k, s := range i.Strings() {
+		if s != `""` {
+			args = append(args, s)
+		}
+
+		if k < len(i.Expressions()) {
+			e := ast.Convert(interpolationToMerge, i.Expressions()[k])
+			args = append(args, ast.NewPApp("$toString", []interface{}{e}, i.ExpressionInfos()[k]))
+		}
+	}
+
+	switch len(args) {
+	case 0:
+		return `""`
+	case 1:
+		return args[0]
+	}
+
+	return ast.NewPApp("$merge", args, i.DebugInfo())
+}
@@ -0,0 +1,67 @@
+package desugar
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDesugarInterpolation(t *testing.T) {
+	i := debug.NewGoInfo(0)
+
+	for _, c := range []struct {
+		strings     []string
+		expressions []interface{}
+		result      string
+	}{
+		{[]string{`""`}, nil, `""`},
+		{[]string{`"foo"`}, nil, `"foo"`},
+		{[]string{`""`, `""`}, []interface{}{"x"}, "($toString x)"},
+		{[]string{`"a"`, `""`}, []interface{}{"x"}, `($merge "a" ($toString x))`},
+		{
+			[]string{`"a"`, `"b"`, `"c"`},
+			[]interface{}{"x", ast.NewInterpolation([]string{`"d"`, `""`}, []interface{}{"y"}, []*debug.Info{i}, i)},
+			`($merge "a" ($toString x) "b" ($toString ($merge "d" ($toString y))) "c")`,
+		},
+	} {
+		is := make([]*debug.Info, 0, len(c.expressions))
+
+		for range c.expressions {
+			is = append(is, i)
+		}
+
+		x := interpolationToMerge(ast.NewInterpolation(c.strings, c.expressions, is, i))
+		assert.Equal(t, c.result, fmt.Sprint(x))
+	}
+}
+
+func TestDesugarInterpolationWithExpressionInfos(t *testing.T) {
+	is := []*debug.Info{debug.NewInfo("", 1, 4, ""), debug.NewInfo("", 1, 8, "")}
+
+	x := interpolationToMerge(ast.NewInterpolation(
+		[]string{`"a"`, `"b"`, `""`},
+		[]interface{}{"x", "y"},
+		is,
+		debug.NewInfo("", 1, 1, "")))
+
+	ps := x.(ast.App).Arguments().Positionals()
+
+	assert.Equal(t, is[0], ps[1].Value().(ast.App).DebugInfo())
+	assert.Equal(t, is[1], ps[3].Value().(ast.App).DebugInfo())
+}
+
+func TestDesugarInterpolationInStatement(t *testing.T) {
+	for _, x := range desugarInterpolation(ast.NewEffect(
+		ast.NewInterpolation([]string{`"Hello, "`, `"!"`}, []interface{}{"name"}, []*debug.Info{debug.NewGoInfo(0)}, debug.NewGoInfo(0)),
+		false,
+		debug.NewGoInfo(0))) {
+		ast.Convert(func(x interface{}) interface{} {
+			_, ok := x.(ast.Interpolation)
+			assert.False(t, ok)
+			return nil
+		}, x)
+	}
+}
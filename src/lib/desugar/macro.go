@@ -0,0 +1,297 @@
+package desugar
+
+import (
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/gensym"
+	"github.com/cloe-lang/cloe/src/lib/scalar"
+)
+
+const maxMacroExpansionDepth = 256
+
+// Macros maps names of macros to functions which convert ASTs represented as
+// values at compile time.
+type Macros map[string]core.Value
+
+type macroExpander struct {
+	macros Macros
+	depth  int
+	errors []error
+}
+
+func newMacroExpander(ms Macros) *macroExpander {
+	return &macroExpander{macros: ms}
+}
+
+func (e *macroExpander) expandStatement(x interface{}) []interface{} {
+	if _, ok := x.(ast.DefMacro); ok {
+		return nil
+	}
+
+	return []interface{}{ast.Convert(e.convert, x)}
+}
+
+func (e *macroExpander) convert(x interface{}) interface{} {
+	a, ok := x.(ast.App)
+
+	if !ok {
+		return nil
+	}
+
+	n, ok := a.Function().(string)
+
+	if !ok {
+		return nil
+	}
+
+	m, ok := e.macros[n]
+
+	if !ok {
+		return nil
+	}
+
+	if e.depth >= maxMacroExpansionDepth {
+		e.errors = append(e.errors, debug.NewError(
+			a.DebugInfo(),
+			"MacroError",
+			"expansion of macro %s is too deep",
+			n))
+		return a
+	}
+
+	y, err := expandMacro(m, a)
+
+	if err != nil {
+		e.errors = append(e.errors, err)
+		return a
+	}
+
+	e.depth++
+	defer func() { e.depth-- }()
+
+	return ast.Convert(e.convert, y)
+}
+
+// expandMacro applies a macro to arguments of an application and converts
+// its result into an AST.
+func expandMacro(m core.Value, a ast.App) (interface{}, error) {
+	i := a.DebugInfo()
+	args := a.Arguments()
+	r := freshNames(newNames(), variables(atoms(args)))
+
+	ps := make([]core.PositionalArgument, 0, len(args.Positionals()))
+
+	for _, p := range args.Positionals() {
+		if p.Expanded() {
+			return nil, debug.NewError(i, "MacroError", "arguments to macros cannot be expanded")
+		}
+
+		ps = append(ps, core.NewPositionalArgument(quote(r.convertAll(p.Value())), false))
+	}
+
+	ks := make([]core.KeywordArgument, 0, len(args.Keywords()))
+
+	for _, k := range args.Keywords() {
+		if k.Name() == "" {
+			return nil, debug.NewError(i, "MacroError", "arguments to macros cannot be expanded")
+		}
+
+		ks = append(ks, core.NewKeywordArgument(k.Name(), quote(r.convertAll(k.Value()))))
+	}
+
+	x, err := unquote(core.App(m, core.NewArguments(ps, ks)), i)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ns := newNames()
+
+	for _, s := range r {
+		ns.add(s)
+	}
+
+	return r.inverse().convertAll(hygienize(x, ns)), nil
+}
+
+// atoms returns names and literals in an expression including ones of
+// parameters and patterns.
+func atoms(x interface{}) names {
+	ns := newNames()
+
+	ast.Convert(func(x interface{}) interface{} {
+		switch x := x.(type) {
+		case string:
+			ns.add(x)
+		case ast.AnonymousFunction:
+			s := x.Signature()
+			ns.merge(newNames(append(s.Positionals(), s.RestPositionals(), s.RestKeywords())...))
+		case ast.MatchCase:
+			ns.merge(atoms(x.Pattern()))
+		}
+
+		return nil
+	}, x)
+
+	return ns
+}
+
+// hygienize renames variables bound in an AST expanded by a macro so that they
+// never capture ones of callers. Variables in arguments to the macro are
+// marked with names in ns beforehand and never renamed.
+func hygienize(x interface{}, ns names) interface{} {
+	return ast.Convert(func(x interface{}) interface{} {
+		switch x := x.(type) {
+		case ast.AnonymousFunction:
+			s := x.Signature()
+			r := freshNames(ns, append(s.Positionals(), s.RestPositionals(), s.RestKeywords()))
+			ks := make([]ast.OptionalParameter, 0, len(s.Keywords()))
+
+			for _, k := range s.Keywords() {
+				if !k.Required() {
+					k = ast.NewOptionalParameter(k.Name(), hygienize(k.DefaultValue(), ns))
+				}
+
+				ks = append(ks, k)
+			}
+
+			return ast.NewAnonymousFunction(
+				ast.NewSignature(
+					r.renameAll(s.Positionals()), r.rename(s.RestPositionals()),
+					ks, r.rename(s.RestKeywords())),
+				hygienize(r.convert(x.Body()), ns))
+		case ast.Match:
+			cs := make([]ast.MatchCase, 0, len(x.Cases()))
+
+			for _, c := range x.Cases() {
+				r := freshNames(ns, patternVariables(c.Pattern()))
+				g := c.Guard()
+
+				if g != nil {
+					g = hygienize(r.convert(g), ns)
+				}
+
+				cs = append(cs, ast.NewGuardedMatchCase(
+					r.convert(c.Pattern()),
+					g,
+					hygienize(r.convert(c.Value()), ns)))
+			}
+
+			return ast.NewMatch(hygienize(x.Value(), ns), cs)
+		}
+
+		return nil
+	}, x)
+}
+
+func patternVariables(p interface{}) []string {
+	ns := atoms(p)
+
+	// Constructors in patterns refer to variables outside.
+	ast.Convert(func(x interface{}) interface{} {
+		if a, ok := x.(ast.App); ok {
+			delete(ns, a.Function().(string))
+		}
+
+		return nil
+	}, p)
+
+	return variables(ns)
+}
+
+// variables returns names in a set except ones of literals and internal
+// variables.
+func variables(ns names) []string {
+	ss := []string{}
+
+	for n := range ns {
+		if n != "" && n[:1] != "$" && !scalar.Defined(n) {
+			ss = append(ss, n)
+		}
+	}
+
+	return ss
+}
+
+// renaming maps names to fresh ones.
+type renaming map[string]string
+
+func freshNames(ns names, ss []string) renaming {
+	r := renaming{}
+
+	for _, s := range ss {
+		if _, ok := ns[s]; !ok && s != "" {
+			r[s] = gensym.GenSym()
+		}
+	}
+
+	return r
+}
+
+func (r renaming) rename(s string) string {
+	if t, ok := r[s]; ok {
+		return t
+	}
+
+	return s
+}
+
+func (r renaming) renameAll(ss []string) []string {
+	ts := make([]string, 0, len(ss))
+
+	for _, s := range ss {
+		ts = append(ts, r.rename(s))
+	}
+
+	return ts
+}
+
+func (r renaming) inverse() renaming {
+	s := make(renaming, len(r))
+
+	for k, v := range r {
+		s[v] = k
+	}
+
+	return s
+}
+
+// convertAll renames names in an expression including ones bound by
+// parameters and patterns.
+func (r renaming) convertAll(x interface{}) interface{} {
+	return ast.Convert(func(x interface{}) interface{} {
+		switch x := x.(type) {
+		case string:
+			return r.rename(x)
+		case ast.AnonymousFunction:
+			s := r.convertAll(x.Signature()).(ast.Signature)
+
+			return ast.NewAnonymousFunction(
+				ast.NewSignature(
+					r.renameAll(s.Positionals()), r.rename(s.RestPositionals()),
+					s.Keywords(), r.rename(s.RestKeywords())),
+				r.convertAll(x.Body()))
+		case ast.MatchCase:
+			g := x.Guard()
+
+			if g != nil {
+				g = r.convertAll(g)
+			}
+
+			return ast.NewGuardedMatchCase(r.convertAll(x.Pattern()), g, r.convertAll(x.Value()))
+		}
+
+		return nil
+	}, x)
+}
+
+func (r renaming) convert(x interface{}) interface{} {
+	return ast.Convert(func(x interface{}) interface{} {
+		if s, ok := x.(string); ok {
+			return r.rename(s)
+		}
+
+		return nil
+	}, x)
+}
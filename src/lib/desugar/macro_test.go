@@ -0,0 +1,135 @@
+package desugar
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/parse"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMacros = Macros{
+	"identity": core.NewLazyFunction(
+		core.NewSignature([]string{"x"}, "", nil, ""),
+		func(vs ...core.Value) core.Value { return vs[0] }),
+	"when": core.NewLazyFunction(
+		core.NewSignature([]string{"c"}, "body", nil, ""),
+		func(vs ...core.Value) core.Value {
+			return core.NewList(
+				core.NewString("if"),
+				vs[0],
+				core.PApp(core.Prepend, core.NewString("seq"), vs[1]),
+				core.NewString("nil"))
+		}),
+	"let-tmp": core.NewLazyFunction(
+		core.NewSignature([]string{"x", "body"}, "", nil, ""),
+		func(vs ...core.Value) core.Value {
+			return core.NewList(
+				core.NewDictionary([]core.KeyValue{
+					{Key: core.NewString("parameters"), Value: core.NewList(core.NewString("tmp"))},
+					{Key: core.NewString("body"), Value: vs[1]},
+				}),
+				vs[0])
+		}),
+	"loop": core.NewLazyFunction(
+		core.NewSignature(nil, "xs", nil, ""),
+		func(vs ...core.Value) core.Value {
+			return core.PApp(core.Prepend, core.NewString("loop"), vs[0])
+		}),
+	"fail": core.NewLazyFunction(
+		core.NewSignature(nil, "", nil, ""),
+		func(...core.Value) core.Value { return core.NewError("MyError", "failed") }),
+}
+
+func TestDesugarWithMacros(t *testing.T) {
+	for _, c := range []struct{ source, result string }{
+		{`(print (identity 42))`, "42"},
+		{`(print (when true (f x) 42))`, "(if true (seq (f x) 42) nil)"},
+		{`(print (when (identity true) (when false 42)))`, "(if true (seq (if false (seq 42) nil)) nil)"},
+	} {
+		m, err := parse.MainModule("<test>", c.source)
+		assert.Nil(t, err)
+
+		e := newMacroExpander(testMacros)
+		ss := e.expandStatement(m[0])
+
+		assert.Equal(t, 0, len(e.errors))
+		assert.Equal(t, 1, len(ss))
+		assert.Equal(t, c.result, fmt.Sprint(ss[0].(ast.Effect).Expr().(ast.App).Arguments().Positionals()[0].Value()))
+	}
+}
+
+func TestDesugarWithMacrosRemovingDefinitions(t *testing.T) {
+	m, err := parse.MainModule("<test>", `(defmacro (foo) "42") (print (foo))`)
+	assert.Nil(t, err)
+
+	ss, err := DesugarWithMacros(m, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ss))
+}
+
+func TestDesugarWithMacrosError(t *testing.T) {
+	for _, s := range []string{
+		`(print (fail))`,
+		`(print (loop 42))`,
+		`(print (identity ..xs))`,
+		`(print (identity . ..xs))`,
+	} {
+		m, err := parse.MainModule("<test>", s)
+		assert.Nil(t, err)
+
+		_, err = DesugarWithMacros(m, testMacros)
+
+		es, ok := err.(debug.Errors)
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(es))
+	}
+}
+
+func TestHygienize(t *testing.T) {
+	m, err := parse.MainModule("<test>", `(print (let-tmp 42 (f tmp)) (let-tmp 42 (f x)))`)
+	assert.Nil(t, err)
+
+	e := newMacroExpander(testMacros)
+	ps := e.expandStatement(m[0])[0].(ast.Effect).Expr().(ast.App).Arguments().Positionals()
+
+	f := ps[0].Value().(ast.App).Function().(ast.AnonymousFunction)
+	assert.NotEqual(t, []string{"tmp"}, f.Signature().Positionals())
+	assert.Equal(t, "(f tmp)", fmt.Sprint(f.Body()))
+
+	f = ps[1].Value().(ast.App).Function().(ast.AnonymousFunction)
+	assert.NotEqual(t, []string{"tmp"}, f.Signature().Positionals())
+	assert.Equal(t, "(f x)", fmt.Sprint(f.Body()))
+}
+
+func TestHygienizeMatch(t *testing.T) {
+	x := hygienize(
+		ast.NewMatch("xs", []ast.MatchCase{
+			ast.NewMatchCase(ast.NewPApp("$list", []interface{}{"y", "z"}, nil), ast.NewPApp("f", []interface{}{"y", "z"}, nil)),
+		}),
+		newNames("z"))
+
+	c := x.(ast.Match).Cases()[0]
+	ys := c.Value().(ast.App).Arguments().Positionals()
+
+	assert.NotEqual(t, "y", ys[0].Value())
+	assert.Equal(t, "z", ys[1].Value())
+	assert.Equal(t, ys[0].Value(), c.Pattern().(ast.App).Arguments().Positionals()[0].Value())
+}
+
+func TestHygienizeMatchWithGuard(t *testing.T) {
+	x := hygienize(
+		ast.NewMatch("xs", []ast.MatchCase{
+			ast.NewGuardedMatchCase("y", ast.NewPApp("f", []interface{}{"y"}, nil), "y"),
+		}),
+		newNames())
+
+	c := x.(ast.Match).Cases()[0]
+
+	assert.NotEqual(t, "y", c.Pattern())
+	assert.Equal(t, c.Pattern(), c.Value())
+	assert.Equal(t, c.Pattern(), c.Guard().(ast.App).Arguments().Positionals()[0].Value())
+}
@@ -20,7 +20,7 @@ func newCasesDesugarer() *casesDesugarer {
 
 func (d *casesDesugarer) Desugar(cs []ast.MatchCase) ast.DefFunction {
 	arg := gensym.GenSym()
-	body := d.desugarCases(arg, cs, "$matchError")
+	body := d.desugarGuardedCases(arg, cs)
 
 	return ast.NewDefFunction(
 		gensym.GenSym(),
@@ -61,6 +61,30 @@ func (d *casesDesugarer) resultApp(f interface{}, args ...interface{}) string {
 	return d.letTempVar(app(f, args...))
 }
 
+// desugarGuardedCases desugars cases up to the first one with a guard. When
+// the guard fails, cases after it are matched by another match expression.
+func (d *casesDesugarer) desugarGuardedCases(v string, cs []ast.MatchCase) interface{} {
+	for i, c := range cs {
+		if c.Guard() == nil {
+			continue
+		}
+
+		dc := interface{}("$matchError")
+
+		if i < len(cs)-1 {
+			dc = d.letTempVar(ast.NewMatch(v, cs[i+1:]))
+		}
+
+		cs = append(
+			cs[:i:i],
+			ast.NewMatchCase(c.Pattern(), app("$if", c.Guard(), c.Value(), dc)))
+
+		return d.desugarCases(v, cs, dc)
+	}
+
+	return d.desugarCases(v, cs, "$matchError")
+}
+
 func (d *casesDesugarer) desugarCases(v interface{}, cs []ast.MatchCase, dc interface{}) interface{} {
 	css := groupCases(cs)
 
@@ -80,6 +104,10 @@ func (d *casesDesugarer) desugarCases(v interface{}, cs []ast.MatchCase, dc inte
 		ks = append(ks, ast.NewSwitchCase("\"dictionary\"", d.desugarDictionaryCases(v, cs, dc)))
 	}
 
+	if cs, ok := css[constructorPattern]; ok {
+		dc = d.desugarConstructorCases(v, cs, dc)
+	}
+
 	if cs, ok := css[scalarPattern]; ok {
 		dc = d.desugarScalarCases(v, cs, dc)
 	}
@@ -118,6 +146,8 @@ func getPatternType(p interface{}) patternType {
 		case consts.Names.DictionaryFunction:
 			return dictionaryPattern
 		}
+
+		return constructorPattern
 	}
 
 	panic(fmt.Errorf("Invalid pattern: %#v", p))
@@ -141,6 +171,8 @@ func isGeneralNamePattern(p interface{}) bool {
 		case consts.Names.DictionaryFunction:
 			return ok
 		}
+
+		return false
 	}
 
 	panic(fmt.Errorf("Invalid pattern: %#v", p))
@@ -181,8 +213,14 @@ func (d *casesDesugarer) desugarListCases(list interface{}, cs []ast.MatchCase,
 			c.Value())
 
 		var ok bool
-		if dc, ok = d.handleGeneralNamePattern(
+		var ec interface{}
+		if dc, ec, ok = d.handleGeneralNamePattern(
 			v, first, cs, c, i, dc, list, rest, d.desugarListCases, d.desugarListCases); ok {
+			// Empty lists never match with general name patterns.
+			if emptyCase == nil {
+				emptyCase = ec
+			}
+
 			break
 		}
 
@@ -289,7 +327,7 @@ func (d *casesDesugarer) desugarDictionaryCasesOfSameKey(dict interface{}, cs []
 			c.Value())
 
 		var ok bool
-		if dc, ok = d.handleGeneralNamePattern(
+		if dc, _, ok = d.handleGeneralNamePattern(
 			v, value, cs, c, i, dc, dict, rest, d.desugarDictionaryCasesOfSameKey, d.desugarDictionaryCases); ok {
 			break
 		}
@@ -318,28 +356,62 @@ func (d *casesDesugarer) desugarDictionaryCasesOfSameKey(dict interface{}, cs []
 	return d.desugarCases(value, cs, dc)
 }
 
+// desugarConstructorCases desugars constructor patterns into list patterns
+// matched with fields of records constructed by the same constructors.
+func (d *casesDesugarer) desugarConstructorCases(v interface{}, cs []ast.MatchCase, dc interface{}) interface{} {
+	type group struct {
+		constructor interface{}
+		cases       []ast.MatchCase
+	}
+
+	gs := []group{}
+
+	for _, c := range cs {
+		a := c.Pattern().(ast.App)
+		c = ast.NewMatchCase(
+			ast.NewApp(consts.Names.ListFunction, a.Arguments(), a.DebugInfo()),
+			c.Value())
+
+		if l := len(gs) - 1; l >= 0 && gs[l].constructor == a.Function() {
+			gs[l].cases = append(gs[l].cases, c)
+		} else {
+			gs = append(gs, group{a.Function(), []ast.MatchCase{c}})
+		}
+	}
+
+	fields := d.matchedApp("$fields", v)
+
+	for i := len(gs) - 1; i >= 0; i-- {
+		g := gs[i]
+		dc = d.resultApp("$if",
+			app("$instance?", v, g.constructor),
+			d.desugarListCases(fields, g.cases, dc),
+			dc)
+	}
+
+	return dc
+}
+
 func (d *casesDesugarer) handleGeneralNamePattern(
 	p, v interface{}, cs []ast.MatchCase, c ast.MatchCase, i int,
 	dc, original, rest interface{},
 	desugarRestCases, desugarCollectionCases func(interface{}, []ast.MatchCase, interface{}) interface{},
-) (interface{}, bool) {
+) (interface{}, interface{}, bool) {
 	if isGeneralNamePattern(p) {
 		d.bindName(p, v)
 
 		if cs := cs[i+1:]; len(cs) > 0 {
-			dc = desugarRestCases(original, cs, dc)
+			dc = d.letTempVar(desugarRestCases(original, cs, dc))
 		}
 
-		dc = d.defaultCaseOfGeneralNamePattern(
+		return d.defaultCaseOfGeneralNamePattern(
 			v,
 			p,
 			desugarCollectionCases(rest, []ast.MatchCase{c}, dc),
-			dc)
-
-		return dc, true
+			dc), dc, true
 	}
 
-	return dc, false
+	return dc, nil, false
 }
 
 func (d *casesDesugarer) defaultCaseOfGeneralNamePattern(v, p, body, dc interface{}) interface{} {
@@ -367,7 +439,7 @@ func (d *casesDesugarer) desugarScalarCases(v interface{}, cs []ast.MatchCase, d
 
 func renameBoundNamesInCase(c ast.MatchCase) ast.MatchCase {
 	p, ns := newPatternRenamer().rename(c.Pattern())
-	return ast.NewMatchCase(p, newValueRenamer(ns).rename(c.Value()))
+	return newValueRenamer(ns).renameCase(p, c)
 }
 
 func equalPatterns(p, q interface{}) bool {
@@ -20,6 +20,7 @@ func TestEqualPatterns(t *testing.T) {
 		app(dictionary),
 		app(dictionary, "123", "true"),
 		app(dictionary, app(dictionary)),
+		app("circle", "r"),
 	} {
 		assert.True(t, equalPatterns(p, p))
 	}
@@ -33,6 +34,8 @@ func TestEqualPatterns(t *testing.T) {
 		{app(dictionary), app(dictionary, "0", "1")},
 		{app(dictionary, "123", "true"), app(dictionary, "456", "true")},
 		{app(dictionary, app(dictionary)), app(dictionary, app(list))},
+		{app("circle", "r"), app("square", "r")},
+		{app("circle", "r"), app(list, "r")},
 	} {
 		assert.True(t, !equalPatterns(ps[0], ps[1]))
 	}
@@ -37,10 +37,21 @@ func TestDesugar(t *testing.T) {
 					ast.NewMatchCase("_", papp("even?", papp("-", "n", "1"))),
 				}), debug.NewGoInfo(0)),
 		}, debug.NewGoInfo(0)),
+		ast.NewLetVar("x", ast.NewMatch("nil", []ast.MatchCase{
+			ast.NewGuardedMatchCase(papp(consts.Names.ListFunction, "x", "y"), papp(">", "x", "0"), "x"),
+			ast.NewGuardedMatchCase(papp(consts.Names.OrPattern, "1", "2"), "true", "nil"),
+			ast.NewMatchCase("x", "x"),
+		})),
 		ast.NewLetVar("x", ast.NewMatch("nil", []ast.MatchCase{
 			ast.NewMatchCase(papp(consts.Names.ListFunction, "1", "x"), "x"),
 			ast.NewMatchCase(papp(consts.Names.DictionaryFunction, "1", "x", `"foo"`, "true"), "x"),
 		})),
+		ast.NewLetVar("x", ast.NewMatch("nil", []ast.MatchCase{
+			ast.NewMatchCase(papp("circle", "1"), "x"),
+			ast.NewMatchCase(papp("rectangle", "w", papp(consts.Names.ListFunction, "h")), "w"),
+			ast.NewMatchCase(papp("circle", "r"), "r"),
+			ast.NewMatchCase(papp("none"), "nil"),
+		})),
 	} {
 		for _, s := range Desugar(s) {
 			t.Logf("%#v", s)
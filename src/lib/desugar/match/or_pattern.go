@@ -0,0 +1,199 @@
+package match
+
+import (
+	"sort"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/gensym"
+	"github.com/cloe-lang/cloe/src/lib/scalar"
+)
+
+// CheckOrPatterns checks if all alternatives of each or-pattern in a module
+// bind the same names.
+func CheckOrPatterns(ss []interface{}) error {
+	es := []error{}
+
+	for _, s := range ss {
+		ast.Convert(func(x interface{}) interface{} {
+			if c, ok := x.(ast.MatchCase); ok {
+				es = append(es, checkOrPatterns(c.Pattern())...)
+			}
+
+			return nil
+		}, s)
+	}
+
+	if len(es) != 0 {
+		return debug.Errors(es)
+	}
+
+	return nil
+}
+
+func checkOrPatterns(p interface{}) []error {
+	a, ok := p.(ast.App)
+
+	if !ok {
+		return nil
+	}
+
+	es := []error{}
+
+	for _, q := range a.Arguments().Positionals() {
+		es = append(es, checkOrPatterns(q.Value())...)
+	}
+
+	if a.Function() != consts.Names.OrPattern {
+		return es
+	}
+
+	ps := a.Arguments().Positionals()
+	ns := boundNames(ps[0].Value())
+
+	for _, q := range ps[1:] {
+		if !equalNames(ns, boundNames(q.Value())) {
+			return append(es, debug.NewError(
+				a.DebugInfo(),
+				"SyntaxError",
+				"all alternatives of an or-pattern must bind the same names"))
+		}
+	}
+
+	return es
+}
+
+// desugarOrPatterns replaces or-patterns in a case with variables. The
+// variables are matched with alternatives of the or-patterns in a guard and
+// a value of the case so that the alternatives share the value.
+func desugarOrPatterns(c ast.MatchCase) ast.MatchCase {
+	p, os := replaceOrPatterns(c.Pattern())
+
+	if len(os) == 0 {
+		return c
+	}
+
+	g, v := c.Guard(), c.Value()
+
+	if g == nil {
+		g = "$true"
+	}
+
+	for i := len(os) - 1; i >= 0; i-- {
+		r, l := os[i].match()
+
+		g = ast.NewMatch(r, []ast.MatchCase{
+			ast.NewMatchCase("$nil", "$false"),
+			ast.NewMatchCase(l, g),
+		})
+		v = ast.NewMatch(r, []ast.MatchCase{ast.NewMatchCase(l, v)})
+	}
+
+	return ast.NewGuardedMatchCase(p, g, v)
+}
+
+// orPattern is an or-pattern replaced with a variable.
+type orPattern struct {
+	variable string
+	pattern  ast.App
+}
+
+// match creates a match expression which results in a list of values of
+// names bound by an or-pattern or nil if none of its alternatives matches.
+// It also returns a list pattern binding the names.
+func (o orPattern) match() (interface{}, interface{}) {
+	ps := o.pattern.Arguments().Positionals()
+	l := app(consts.Names.ListFunction, boundNames(ps[0].Value())...)
+	cs := make([]ast.MatchCase, 0, len(ps)+1)
+
+	for _, p := range ps {
+		cs = append(cs, ast.NewMatchCase(p.Value(), l))
+	}
+
+	return ast.NewMatch(o.variable, append(cs, ast.NewMatchCase(gensym.GenSym(), "$nil"))), l
+}
+
+func replaceOrPatterns(p interface{}) (interface{}, []orPattern) {
+	a, ok := p.(ast.App)
+
+	if !ok {
+		return p, nil
+	} else if a.Function() == consts.Names.OrPattern {
+		o := orPattern{gensym.GenSym(), a}
+		return o.variable, []orPattern{o}
+	}
+
+	ps := make([]ast.PositionalArgument, 0, len(a.Arguments().Positionals()))
+	os := []orPattern{}
+
+	for i, q := range a.Arguments().Positionals() {
+		// Keys of dictionary patterns are never replaced.
+		if a.Function() == consts.Names.DictionaryFunction && i%2 == 0 && !q.Expanded() {
+			ps = append(ps, q)
+			continue
+		}
+
+		r, qs := replaceOrPatterns(q.Value())
+		ps = append(ps, ast.NewPositionalArgument(r, q.Expanded()))
+		os = append(os, qs...)
+	}
+
+	return ast.NewApp(a.Function(), ast.NewArguments(ps, nil), a.DebugInfo()), os
+}
+
+// boundNames returns sorted names bound by a pattern.
+func boundNames(p interface{}) []interface{} {
+	ns := map[string]bool{}
+	addBoundNames(p, ns)
+
+	ss := make([]string, 0, len(ns))
+
+	for n := range ns {
+		ss = append(ss, n)
+	}
+
+	sort.Strings(ss)
+
+	xs := make([]interface{}, 0, len(ss))
+
+	for _, s := range ss {
+		xs = append(xs, s)
+	}
+
+	return xs
+}
+
+func addBoundNames(p interface{}, ns map[string]bool) {
+	switch x := p.(type) {
+	case string:
+		if !scalar.Defined(x) {
+			ns[x] = true
+		}
+	case ast.App:
+		ps := x.Arguments().Positionals()
+
+		// All alternatives of or-patterns bind the same names.
+		if x.Function() == consts.Names.OrPattern {
+			ps = ps[:1]
+		}
+
+		for _, q := range ps {
+			addBoundNames(q.Value(), ns)
+		}
+	}
+}
+
+func equalNames(xs, ys []interface{}) bool {
+	if len(xs) != len(ys) {
+		return false
+	}
+
+	for i := range xs {
+		if xs[i] != ys[i] {
+			return false
+		}
+	}
+
+	return true
+}
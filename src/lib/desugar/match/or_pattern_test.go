@@ -0,0 +1,84 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOrPatterns(t *testing.T) {
+	list := consts.Names.ListFunction
+	or := consts.Names.OrPattern
+
+	for _, c := range []struct {
+		pattern interface{}
+		ok      bool
+	}{
+		{"x", true},
+		{papp(or, "1", "2"), true},
+		{papp(or, papp(list, "x", "1"), papp(list, "1", "x")), true},
+		{papp(list, papp(or, "1", "2"), papp(or, papp(list, "y"), "y")), true},
+		{papp(or, papp(list, "x"), "y"), false},
+		{papp(or, "1", "x"), false},
+		{papp(list, papp(or, papp(list, "x"), "1")), false},
+	} {
+		err := CheckOrPatterns([]interface{}{
+			ast.NewLetVar("x", ast.NewMatch("nil", []ast.MatchCase{ast.NewMatchCase(c.pattern, "nil")})),
+		})
+
+		if c.ok {
+			assert.Nil(t, err)
+		} else {
+			es, ok := err.(debug.Errors)
+			assert.True(t, ok)
+			assert.Equal(t, 1, len(es))
+			assert.Equal(t, "SyntaxError", es[0].(*debug.Error).Name())
+		}
+	}
+}
+
+func TestDesugarOrPatterns(t *testing.T) {
+	list := consts.Names.ListFunction
+	or := consts.Names.OrPattern
+
+	c := desugarOrPatterns(ast.NewMatchCase("x", "x"))
+	assert.Equal(t, "x", c.Pattern())
+	assert.Nil(t, c.Guard())
+
+	ps := []interface{}{}
+
+	for i := 0; i < 8; i++ {
+		ps = append(ps, papp(or, "1", "2", "3"))
+	}
+
+	c = desugarOrPatterns(ast.NewMatchCase(papp(append([]interface{}{list}, ps...)...), "x"))
+
+	for _, p := range c.Pattern().(ast.App).Arguments().Positionals() {
+		_, ok := p.Value().(string)
+		assert.True(t, ok)
+	}
+
+	assert.NotNil(t, c.Guard())
+	assert.Equal(t, 1, len(c.Value().(ast.Match).Cases()))
+}
+
+func TestBoundNames(t *testing.T) {
+	list := consts.Names.ListFunction
+	or := consts.Names.OrPattern
+
+	for _, c := range []struct {
+		pattern interface{}
+		names   []interface{}
+	}{
+		{"42", []interface{}{}},
+		{"x", []interface{}{"x"}},
+		{papp(list, "y", "x", "1"), []interface{}{"x", "y"}},
+		{papp("circle", "r"), []interface{}{"r"}},
+		{papp(or, papp(list, "x"), "x"), []interface{}{"x"}},
+	} {
+		assert.Equal(t, c.names, boundNames(c.pattern))
+	}
+}
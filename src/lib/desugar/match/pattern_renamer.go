@@ -10,11 +10,12 @@ import (
 )
 
 type patternRenamer struct {
-	nameMap map[string]string
+	nameMap     map[string]string
+	alternative bool // true if names are shared by alternatives of or-patterns
 }
 
 func newPatternRenamer() *patternRenamer {
-	return &patternRenamer{map[string]string{}}
+	return &patternRenamer{map[string]string{}, false}
 }
 
 func (r *patternRenamer) rename(p interface{}) (interface{}, map[string]string) {
@@ -27,6 +28,8 @@ func (r *patternRenamer) renameNames(p interface{}) interface{} {
 	case string:
 		if scalar.Defined(x) {
 			return x
+		} else if n, ok := r.nameMap[x]; ok && r.alternative {
+			return n
 		}
 
 		r.nameMap[x] = gensym.GenSym()
@@ -43,7 +46,29 @@ func (r *patternRenamer) renameNames(p interface{}) interface{} {
 			}
 
 			return ast.NewApp(x.Function(), ast.NewArguments(ps, nil), x.DebugInfo())
+		case consts.Names.OrPattern:
+			s := &patternRenamer{map[string]string{}, true}
+			ps := make([]ast.PositionalArgument, 0, len(x.Arguments().Positionals()))
+
+			for _, p := range x.Arguments().Positionals() {
+				ps = append(ps, ast.NewPositionalArgument(s.renameNames(p.Value()), false))
+			}
+
+			for n, m := range s.nameMap {
+				r.nameMap[n] = m
+			}
+
+			return ast.NewApp(x.Function(), ast.NewArguments(ps, nil), x.DebugInfo())
+		}
+
+		// Names of constructors are not bound by patterns.
+		ps := make([]ast.PositionalArgument, 0, len(x.Arguments().Positionals()))
+
+		for _, p := range x.Arguments().Positionals() {
+			ps = append(ps, ast.NewPositionalArgument(r.renameNames(p.Value()), false))
 		}
+
+		return ast.NewApp(x.Function(), ast.NewArguments(ps, nil), x.DebugInfo())
 	}
 
 	panic(fmt.Errorf("Invalid pattern: %#v", p))
@@ -5,6 +5,7 @@ type patternType int
 const (
 	listPattern patternType = iota
 	dictionaryPattern
+	constructorPattern
 	scalarPattern
 	namePattern
 )
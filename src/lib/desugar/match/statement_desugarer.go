@@ -36,7 +36,7 @@ func (d *statementDesugarer) Desugar(x interface{}) interface{} {
 			cs := make([]ast.MatchCase, 0, len(x.Cases()))
 
 			for _, c := range x.Cases() {
-				cs = append(cs, renameBoundNamesInCase(ast.NewMatchCase(c.Pattern(), c.Value())))
+				cs = append(cs, renameBoundNamesInCase(desugarOrPatterns(c)))
 			}
 
 			f := newCasesDesugarer().Desugar(cs)
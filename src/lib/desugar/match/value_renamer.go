@@ -42,7 +42,7 @@ func (r valueRenamer) rename(v interface{}) interface{} {
 
 		for _, c := range x.Cases() {
 			p, ns := newPatternRenamer().rename(c.Pattern())
-			cs = append(cs, ast.NewMatchCase(p, r.extend(ns).rename(c.Value())))
+			cs = append(cs, r.extend(ns).renameCase(p, c))
 		}
 
 		return ast.NewMatch(r.rename(x.Value()), cs)
@@ -51,6 +51,17 @@ func (r valueRenamer) rename(v interface{}) interface{} {
 	panic(fmt.Errorf("Invalid value: %#v", v))
 }
 
+// renameCase renames a guard and a value of a case with a renamed pattern.
+func (r valueRenamer) renameCase(p interface{}, c ast.MatchCase) ast.MatchCase {
+	g := c.Guard()
+
+	if g != nil {
+		g = r.rename(g)
+	}
+
+	return ast.NewGuardedMatchCase(p, g, r.rename(c.Value()))
+}
+
 func (r valueRenamer) extend(ns map[string]string) valueRenamer {
 	ms := make(map[string]string, len(r.nameMap)+len(ns))
 
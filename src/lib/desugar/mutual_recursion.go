@@ -48,20 +48,22 @@ func desugarMutualRecursion(mr ast.MutualRecursion) []interface{} {
 	for i, f := range fs {
 		recs = append(
 			recs,
-			ast.NewLetVar(
+			ast.NewLetVarWithInfo(
 				f.Name(),
 				ast.NewPApp(
 					consts.Names.IndexFunction,
 					[]interface{}{recsList, fmt.Sprint(i + 1)},
-					f.DebugInfo())))
+					f.DebugInfo()),
+				f.DebugInfo()))
 	}
 
 	return append(
 		unrecs,
 		append(
-			[]interface{}{ast.NewLetVar(
+			[]interface{}{ast.NewLetVarWithInfo(
 				recsList,
-				ast.NewPApp("$ys", letStatementsToNames(unrecs), mr.DebugInfo()))},
+				ast.NewPApp("$ys", letStatementsToNames(unrecs), mr.DebugInfo()),
+				mr.DebugInfo())},
 			recs...)...)
 }
 
@@ -28,7 +28,7 @@ func removeAliases(x interface{}) []interface{} {
 
 			for i, l := range ls {
 				l := l.(ast.LetVar)
-				ls[i] = ast.NewLetVar(l.Name(), r(l.Expr()))
+				ls[i] = ast.NewLetVarWithInfo(l.Name(), r(l.Expr()), l.DebugInfo())
 			}
 
 			b = r(b)
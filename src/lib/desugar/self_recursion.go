@@ -24,7 +24,10 @@ func desugarSelfRecursiveStatement(x interface{}) []interface{} {
 					x.Lets(),
 					x.Body(),
 					x.DebugInfo()),
-				ast.NewLetVar(x.Name(), ast.NewPApp("$y", []interface{}{unrec}, x.DebugInfo())),
+				ast.NewLetVarWithInfo(
+					x.Name(),
+					ast.NewPApp("$y", []interface{}{unrec}, x.DebugInfo()),
+					x.DebugInfo()),
 			}
 		}
 
@@ -0,0 +1,37 @@
+package desugar
+
+import (
+	"github.com/cloe-lang/cloe/src/lib/ast"
+)
+
+func desugarThread(x interface{}) []interface{} {
+	return []interface{}{ast.Convert(threadToApps, x)}
+}
+
+// threadToApps converts a threading form into nested applications keeping
+// debug information of each step.
+func threadToApps(x interface{}) interface{} {
+	t, ok := x.(ast.Thread)
+
+	if !ok {
+		return nil
+	}
+
+	v := ast.Convert(threadToApps, t.Value())
+
+	for _, s := range t.Steps() {
+		a := ast.Convert(threadToApps, s).(ast.App)
+		p := ast.NewPositionalArgument(v, false)
+		ps := a.Arguments().Positionals()
+
+		if t.Last() {
+			ps = append(append(make([]ast.PositionalArgument, 0, len(ps)+1), ps...), p)
+		} else {
+			ps = append([]ast.PositionalArgument{p}, ps...)
+		}
+
+		v = ast.NewApp(a.Function(), ast.NewArguments(ps, a.Arguments().Keywords()), a.DebugInfo())
+	}
+
+	return v
+}
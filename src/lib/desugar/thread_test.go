@@ -0,0 +1,75 @@
+package desugar
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDesugarThread(t *testing.T) {
+	i := debug.NewGoInfo(0)
+	n := ast.NewArguments(nil, nil)
+	ks := []ast.KeywordArgument{ast.NewKeywordArgument("less", "g")}
+
+	for _, c := range []struct {
+		thread ast.Thread
+		result string
+	}{
+		{ast.NewThread(false, "x", []ast.App{ast.NewApp("f", n, i)}, i), "(f x)"},
+		{ast.NewThread(true, "x", []ast.App{ast.NewApp("f", n, i)}, i), "(f x)"},
+		{
+			ast.NewThread(false, "x", []ast.App{ast.NewPApp("f", []interface{}{"a", "b"}, i)}, i),
+			"(f x a b)",
+		},
+		{
+			ast.NewThread(true, "x", []ast.App{ast.NewPApp("f", []interface{}{"a", "b"}, i)}, i),
+			"(f a b x)",
+		},
+		{
+			ast.NewThread(true, "xs", []ast.App{
+				ast.NewApp("sort", ast.NewArguments(nil, ks), i),
+				ast.NewPApp("map", []interface{}{"f"}, i),
+				ast.NewPApp("filter", []interface{}{"p"}, i),
+			}, i),
+			"(filter p (map f (sort xs . less g)))",
+		},
+		{
+			ast.NewThread(false, ast.NewThread(true, "x", []ast.App{ast.NewApp("f", n, i)}, i), []ast.App{
+				ast.NewApp("g", n, i),
+			}, i),
+			"(g (f x))",
+		},
+	} {
+		assert.Equal(t, c.result, fmt.Sprint(threadToApps(c.thread)))
+	}
+}
+
+func TestDesugarThreadWithDebugInfo(t *testing.T) {
+	is := []*debug.Info{debug.NewGoInfo(0), debug.NewGoInfo(0)}
+	n := ast.NewArguments(nil, nil)
+
+	a := threadToApps(ast.NewThread(
+		false,
+		"x",
+		[]ast.App{ast.NewApp("f", n, is[0]), ast.NewApp("g", n, is[1])},
+		debug.NewGoInfo(0))).(ast.App)
+
+	assert.Equal(t, is[1], a.DebugInfo())
+	assert.Equal(t, is[0], a.Arguments().Positionals()[0].Value().(ast.App).DebugInfo())
+}
+
+func TestDesugarThreadInStatement(t *testing.T) {
+	for _, x := range desugarThread(ast.NewEffect(
+		ast.NewThread(false, "x", []ast.App{ast.NewPApp("f", []interface{}{"y"}, debug.NewGoInfo(0))}, debug.NewGoInfo(0)),
+		false,
+		debug.NewGoInfo(0))) {
+		ast.Convert(func(x interface{}) interface{} {
+			_, ok := x.(ast.Thread)
+			assert.False(t, ok)
+			return nil
+		}, x)
+	}
+}
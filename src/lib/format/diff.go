@@ -0,0 +1,124 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+type diffLine struct {
+	kind byte // ' ', '-', or '+'
+	text string
+}
+
+// Diff returns a unified diff of lines between 2 versions of a file.
+func Diff(file, old, new string) string {
+	ls := diffLines(splitLines(old), splitLines(new))
+	ss := []string{}
+
+	for i := 0; i < len(ls); {
+		if ls[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		s := max(i-diffContext, 0)
+		e := i
+
+		for j := i; j < len(ls) && j-e <= 2*diffContext; j++ {
+			if ls[j].kind != ' ' {
+				e = j
+			}
+		}
+
+		e = min(e+diffContext+1, len(ls))
+		ss = append(ss, hunk(ls, s, e))
+		i = e
+	}
+
+	if len(ss) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("--- %s\n+++ %s\n", file, file) + strings.Join(ss, "")
+}
+
+func hunk(ls []diffLine, s, e int) string {
+	o, n := 1, 1
+
+	for _, l := range ls[:s] {
+		if l.kind != '+' {
+			o++
+		}
+
+		if l.kind != '-' {
+			n++
+		}
+	}
+
+	ss := []string{}
+	ol, nl := 0, 0
+
+	for _, l := range ls[s:e] {
+		if l.kind != '+' {
+			ol++
+		}
+
+		if l.kind != '-' {
+			nl++
+		}
+
+		ss = append(ss, string(l.kind)+l.text+"\n")
+	}
+
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", o, ol, n, nl) + strings.Join(ss, "")
+}
+
+// diffLines computes a shortest edit script between 2 lists of lines using a
+// table of longest common subsequences.
+func diffLines(xs, ys []string) []diffLine {
+	t := make([][]int, len(xs)+1)
+
+	for i := range t {
+		t[i] = make([]int, len(ys)+1)
+	}
+
+	for i := len(xs) - 1; i >= 0; i-- {
+		for j := len(ys) - 1; j >= 0; j-- {
+			if xs[i] == ys[j] {
+				t[i][j] = t[i+1][j+1] + 1
+			} else {
+				t[i][j] = max(t[i+1][j], t[i][j+1])
+			}
+		}
+	}
+
+	ls := make([]diffLine, 0, len(xs)+len(ys))
+	i, j := 0, 0
+
+	for i < len(xs) || j < len(ys) {
+		switch {
+		case i < len(xs) && j < len(ys) && xs[i] == ys[j]:
+			ls = append(ls, diffLine{' ', xs[i]})
+			i++
+			j++
+		case j == len(ys) || i < len(xs) && t[i+1][j] >= t[i][j+1]:
+			ls = append(ls, diffLine{'-', xs[i]})
+			i++
+		default:
+			ls = append(ls, diffLine{'+', ys[j]})
+			j++
+		}
+	}
+
+	return ls
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
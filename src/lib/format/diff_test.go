@@ -0,0 +1,26 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	for _, c := range []struct{ old, new, diff string }{
+		{"foo\n", "foo\n", ""},
+		{"foo\n", "bar\n", "--- x\n+++ x\n@@ -1,1 +1,1 @@\n-foo\n+bar\n"},
+		{
+			"a\nb\nc\nd\ne\nf\ng\nh\ni\n",
+			"a\nb\nc\nd\nE\nf\ng\nh\ni\n",
+			"--- x\n+++ x\n@@ -2,7 +2,7 @@\n b\n c\n d\n-e\n+E\n f\n g\n h\n",
+		},
+		{
+			"a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n",
+			"A\nb\nc\nd\ne\nf\ng\nh\ni\nj\nK\n",
+			"--- x\n+++ x\n@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n@@ -8,4 +8,4 @@\n h\n i\n j\n-k\n+K\n",
+		},
+	} {
+		assert.Equal(t, c.diff, Diff("x", c.old, c.new))
+	}
+}
@@ -0,0 +1,55 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/parse"
+)
+
+const (
+	defString        = "def"
+	defTypeString    = "deftype"
+	lambdaString     = "\\"
+	letString        = "let"
+	matchString      = "match"
+	mutualRecString  = "mr"
+	shebangPrefix    = "#!"
+	threadString     = "->"
+	threadLastString = "->>"
+)
+
+// Format formats source code of a module in a canonical style keeping its
+// comments and shebang.
+func Format(file, source string) (string, error) {
+	if _, err := parse.MainModule(file, source); err != nil {
+		return "", err
+	}
+
+	s := ""
+
+	if strings.HasPrefix(source, shebangPrefix) {
+		i := strings.IndexByte(source, '\n')
+
+		if i < 0 {
+			return strings.TrimRight(source, spaceChars) + "\n", nil
+		}
+
+		s, source = strings.TrimRight(source[:i], spaceChars)+"\n", source[i+1:]
+	}
+
+	ns, cs, err := parseSyntax(source)
+
+	if err != nil {
+		return "", err
+	} else if len(ns) == 0 && len(cs) == 0 {
+		return s, nil
+	}
+
+	ls := strings.Split((&printer{}).module(ns, cs), "\n")
+
+	for i, l := range ls {
+		ls[i] = strings.TrimRight(l, spaceChars)
+	}
+
+	return s + strings.TrimRight(strings.Join(ls, "\n"), "\n") + "\n", nil
+}
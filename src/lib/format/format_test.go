@@ -0,0 +1,97 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	for _, c := range []struct{ source, formatted string }{
+		{"", ""},
+		{"(print   42)", "(print 42)\n"},
+		{"#!/usr/bin/env cloe\n(print 42)\n", "#!/usr/bin/env cloe\n(print 42)\n"},
+		{"(import \"http\")\n\n\n(print 42)", "(import \"http\")\n\n(print 42)\n"},
+		{
+			"; foo\n(def (f x)   ; bar\n (let y (+ x 1))\n  ; baz\n  (g y))\n",
+			"; foo\n(def (f x) ; bar\n  (let y (+ x 1))\n  ; baz\n  (g y))\n",
+		},
+		{
+			"(mr (def (even? n) (if (= n 0) true (odd? (- n 1)))) (def (odd? n) (if (= n 0) false (even? (- n 1)))))",
+			"(mr\n  (def (even? n) (if (= n 0) true (odd? (- n 1))))\n  (def (odd? n) (if (= n 0) false (even? (- n 1)))))\n",
+		},
+		{
+			"(print (match x 42 \"foo\" [y] y))",
+			"(print (match x\n  42 \"foo\"\n  [y] y))\n",
+		},
+		{
+			"(print (->> [3 1 2] (sort . less >) (map (\\ (x) (* x 10))) (filter (\\ (x) (> x 10)))))",
+			"(print (->> [3 1 2]\n  (sort . less >)\n  (map (\\ (x) (* x 10)))\n  (filter (\\ (x) (> x 10)))))\n",
+		},
+		{
+			"(print   $\"Hello, {(@ user  \"name\")}! {{\"a\" 1}} {$\"{\"}\"}\"}\")",
+			"(print $\"Hello, {(@ user  \"name\")}! {{\"a\" 1}} {$\"{\"}\"}\"}\")\n",
+		},
+		{
+			"(print   `C:\\(foo)\\\"bar\"`)",
+			"(print `C:\\(foo)\\\"bar\"`)\n",
+		},
+		{
+			"(print \"\"\"\n  say \"hi\" (\n  \"\"\"   )",
+			"(print \"\"\"\n  say \"hi\" (\n  \"\"\")\n",
+		},
+		{
+			"(print \"\"\"say \"hi\"\"\"\"   42)",
+			"(print \"\"\"say \"hi\"\"\"\" 42)\n",
+		},
+		{
+			"(let f (\\ (x) (+ x 1)))",
+			"(let f (\\ (x) (+ x 1)))\n",
+		},
+		{
+			"(http.request \"https://example.com/foo/bar/baz\" . method \"POST\" headers {\"a\" \"b\"} body \"foo\" ..options)",
+			"(http.request\n  \"https://example.com/foo/bar/baz\"\n  . method  \"POST\"\n    headers {\"a\" \"b\"}\n    body    \"foo\"\n    ..options)\n",
+		},
+		{
+			"(def (f x:number ..ys:list[ number|string ]) :dictionary[string  list[number]] x)",
+			"(def (f x:number ..ys:list[number|string]) :dictionary[string list[number]] x)\n",
+		},
+		{
+			"(def (f x:number) :number (let y (+ x 1)) y)",
+			"(def (f x:number) :number\n  (let y (+ x 1))\n  y)\n",
+		},
+		{
+			"(def (f x) :number\n  (+ x 1))",
+			"(def (f x) :number (+ x 1))\n",
+		},
+		{
+			"(deftype shape (circle radius) (rectangle width height) (triangle a b c) (polygon points))",
+			"(deftype shape\n  (circle radius)\n  (rectangle width height)\n  (triangle a b c)\n  (polygon points))\n",
+		},
+		{
+			"(print (match x (circle r) r (rectangle w h) (* w h)))",
+			"(print (match x\n  (circle r) r\n  (rectangle w h) (* w h)))\n",
+		},
+		{
+			"(seq! (print 1) ; foo\n (print 2))\n; bar\n",
+			"(seq!\n  (print 1) ; foo\n  (print 2))\n; bar\n",
+		},
+	} {
+		s, err := Format("", c.source)
+
+		assert.Nil(t, err)
+		assert.Equal(t, c.formatted, s)
+
+		s, err = Format("", s)
+
+		assert.Nil(t, err)
+		assert.Equal(t, c.formatted, s)
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	for _, s := range []string{"(print 42", "(print 42))", "(let x 42", "(def (f x:list[number) x)", "(print $\"{x\")", "(print `foo)", "(print \"\"\"foo\"\")"} {
+		_, err := Format("", s)
+		assert.NotNil(t, err)
+	}
+}
@@ -0,0 +1,356 @@
+package format
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxWidth     = 80
+	indentWidth  = 2
+	keywordsMark = "."
+)
+
+type printer struct {
+	builder strings.Builder
+	column  int
+	indent  int    // indentation of a current line
+	fresh   bool   // true if nothing is written in a current line after indentation
+	comment string // comment to be written at the end of a current line
+}
+
+func (p *printer) module(ns []*node, footer []string) string {
+	p.fresh = true
+
+	for i, n := range ns {
+		if i != 0 {
+			p.newline(0)
+		}
+
+		p.comments(n.comments, 0, i == 0)
+		p.node(n)
+	}
+
+	for _, c := range footer {
+		if !p.fresh {
+			p.newline(0)
+		}
+
+		p.write(c)
+	}
+
+	p.newline(0)
+
+	return p.builder.String()
+}
+
+func (p *printer) node(n *node) {
+	if s, ok := flat(n); ok && p.column+utf8.RuneCountInString(s) <= maxWidth {
+		p.write(s)
+	} else {
+		p.write(n.prefix)
+		p.list(n)
+	}
+
+	if n.comment != "" {
+		if p.comment != "" {
+			p.comment += " "
+		}
+
+		p.comment += n.comment
+	}
+}
+
+func (p *printer) list(n *node) {
+	c := p.indent
+	i := c + indentWidth
+
+	switch n.text {
+	case "[", "{":
+		c = p.column
+		i = c + 1
+	}
+
+	p.write(n.text)
+	ns := n.children
+
+	switch n.text {
+	case "[":
+		p.sequence(ns, i, true)
+	case "{":
+		p.pairs(ns, i, 0, true)
+	default:
+		h := headLength(n)
+
+		for j, m := range ns[:h] {
+			if j != 0 {
+				p.write(" ")
+			}
+
+			p.inline(m, i)
+		}
+
+		if h == 0 {
+			break
+		} else if hangs(n) {
+			p.write(" ")
+			p.inline(ns[h], i)
+		} else if isForm(n, matchString) {
+			p.pairs(ns[h:], i, 0, false)
+		} else if k := keywordsIndex(ns[h:]); k >= 0 {
+			p.sequence(ns[h:h+k], i, false)
+			p.newline(i)
+			p.comments(ns[h+k].comments, i, true)
+			p.node(ns[h+k])
+			p.write(" ")
+			p.pairs(ns[h+k+1:], i+len(keywordsMark)+1, keyWidth(ns[h+k+1:]), true)
+		} else {
+			p.sequence(ns[h:], i, false)
+		}
+	}
+
+	if len(n.footer) != 0 {
+		for _, s := range n.footer {
+			if s != "" {
+				p.newline(i)
+				p.write(s)
+			}
+		}
+
+		p.newline(c)
+	}
+
+	p.write(n.closer())
+}
+
+// sequence writes nodes one by one in lines. If sameLine is true, the first
+// node is written in a current line.
+func (p *printer) sequence(ns []*node, indent int, sameLine bool) {
+	for j, n := range ns {
+		if j != 0 || !sameLine {
+			p.newline(indent)
+			p.comments(n.comments, indent, j == 0)
+			p.node(n)
+		} else {
+			p.inline(n, indent)
+		}
+	}
+}
+
+// pairs writes pairs of nodes in lines padding keys into a width of w.
+// Expanded arguments are written alone in their lines.
+func (p *printer) pairs(ns []*node, indent, w int, sameLine bool) {
+	for j, g := range groupPairs(ns) {
+		if j != 0 || !sameLine {
+			p.newline(indent)
+			p.comments(g[0].comments, indent, j == 0)
+			p.node(g[0])
+		} else {
+			p.inline(g[0], indent)
+		}
+
+		if len(g) == 2 {
+			p.write(strings.Repeat(" ", max(w-width(g[0]), 0)+1))
+			p.inline(g[1], indent+indentWidth)
+		}
+	}
+}
+
+// inline writes a node after other nodes in a current line. If the node has
+// comments before it, it is moved to a new line.
+func (p *printer) inline(n *node, indent int) {
+	cs := []string{}
+
+	for _, c := range n.comments {
+		if c != "" {
+			cs = append(cs, c)
+		}
+	}
+
+	if len(cs) != 0 && !p.fresh {
+		p.newline(indent)
+	}
+
+	p.comments(cs, indent, true)
+	p.node(n)
+}
+
+// comments writes comments in lines. A current line must be empty.
+func (p *printer) comments(cs []string, indent int, first bool) {
+	for j, c := range cs {
+		if c == "" && first && j == 0 {
+			continue
+		}
+
+		p.write(c)
+		p.newline(indent)
+	}
+}
+
+func (p *printer) write(s string) {
+	if s == "" {
+		return
+	}
+
+	p.builder.WriteString(s)
+	p.column += utf8.RuneCountInString(s)
+	p.fresh = false
+}
+
+func (p *printer) newline(indent int) {
+	if p.comment != "" {
+		p.builder.WriteString(" " + p.comment)
+		p.comment = ""
+	}
+
+	p.builder.WriteString("\n" + strings.Repeat(" ", indent))
+	p.column = indent
+	p.indent = indent
+	p.fresh = true
+}
+
+// flat returns a representation of a node in a line. It fails if the node
+// contains any comments or forms which must be written in multiple lines.
+func flat(n *node) (string, bool) {
+	if !n.isList() {
+		return n.prefix + n.text, true
+	} else if len(n.footer) != 0 {
+		return "", false
+	}
+
+	ss := make([]string, 0, len(n.children))
+
+	if forcesBreak(n) {
+		return "", false
+	}
+
+	for _, m := range n.children {
+		if len(m.comments) != 0 || m.comment != "" {
+			return "", false
+		}
+
+		s, ok := flat(m)
+
+		if !ok {
+			return "", false
+		}
+
+		ss = append(ss, s)
+	}
+
+	return n.prefix + n.text + strings.Join(ss, " ") + n.closer(), true
+}
+
+// hangs returns true if the only argument of a function call in a list can be
+// written in the first line of the list.
+func hangs(n *node) bool {
+	ns := n.children
+
+	if headLength(n) != 1 || len(ns) != 2 || ns[1].prefix != "" || len(ns[1].comments) != 0 {
+		return false
+	}
+
+	_, ok := flat(ns[0])
+	return ok && ns[0].comment == "" && ns[0].text != keywordsMark
+}
+
+// forcesBreak returns true if a list should be written in multiple lines even
+// when it fits in a line.
+func forcesBreak(n *node) bool {
+	switch {
+	case isForm(n, defString):
+		return len(n.children) > 3 && !hasResultType(n) || len(n.children) > 4
+	case isForm(n, mutualRecString):
+		return len(n.children) > 2
+	case isForm(n, matchString):
+		return len(n.children) > 4
+	}
+
+	return false
+}
+
+// headLength returns a number of elements written in the first line of a list
+// in parentheses.
+func headLength(n *node) int {
+	h := 1
+
+	switch {
+	case hasResultType(n):
+		h = 3
+	case isForm(n, defString), isForm(n, defTypeString), isForm(n, letString),
+		isForm(n, matchString), isForm(n, lambdaString),
+		isForm(n, threadString), isForm(n, threadLastString):
+		h = 2
+	}
+
+	return min(h, len(n.children))
+}
+
+// hasResultType returns true if a list is a function definition with a type
+// annotation of its results.
+func hasResultType(n *node) bool {
+	if !isForm(n, defString) || len(n.children) < 4 {
+		return false
+	}
+
+	m := n.children[2]
+	return !m.isList() && m.prefix == "" && strings.HasPrefix(m.text, ":")
+}
+
+func isForm(n *node, s string) bool {
+	return n.text == "(" && len(n.children) != 0 && n.children[0].prefix == "" && n.children[0].text == s
+}
+
+func keywordsIndex(ns []*node) int {
+	for i, n := range ns {
+		if n.prefix == "" && n.text == keywordsMark {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func groupPairs(ns []*node) [][]*node {
+	gs := [][]*node{}
+
+	for i := 0; i < len(ns); i++ {
+		if ns[i].prefix != "" || i+1 == len(ns) {
+			gs = append(gs, ns[i:i+1])
+		} else {
+			gs = append(gs, ns[i:i+2])
+			i++
+		}
+	}
+
+	return gs
+}
+
+// keyWidth returns the maximum width of keys in pairs.
+func keyWidth(ns []*node) int {
+	w := 0
+
+	for _, g := range groupPairs(ns) {
+		if len(g) == 2 {
+			w = max(w, width(g[0]))
+		}
+	}
+
+	return w
+}
+
+func width(n *node) int {
+	if n.isList() {
+		return 0
+	}
+
+	return utf8.RuneCountInString(n.prefix + n.text)
+}
+
+func max(i, j int) int {
+	if i > j {
+		return i
+	}
+
+	return j
+}
@@ -0,0 +1,353 @@
+package format
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	commentChar = ';'
+	spaceChars  = " \t\n\r"
+	openers     = "([{"
+	closers     = ")]}"
+	atomEnds    = spaceChars + openers + closers + "\"\\;`"
+)
+
+// node is a node of a concrete syntax tree. Unlike ASTs, it keeps comments and
+// blank lines around expressions.
+type node struct {
+	prefix   string   // ".." for expanded arguments
+	text     string   // text of an atom or an opening bracket of a list
+	children []*node  // elements of a list
+	comments []string // comments on lines before a node where "" means a blank line
+	comment  string   // comment at the end of a line where a node ends
+	footer   []string // comments before a closing bracket
+}
+
+func (n *node) isList() bool {
+	return strings.Contains(openers, n.text)
+}
+
+func (n *node) closer() string {
+	return string(closers[strings.Index(openers, n.text)])
+}
+
+type syntaxParser struct {
+	source   []rune
+	position int
+}
+
+func parseSyntax(source string) ([]*node, []string, error) {
+	p := &syntaxParser{source: []rune(source)}
+	return p.nodes(0)
+}
+
+// nodes parses nodes until a closing bracket c or the end of source if c is 0.
+// It returns comments before the closing bracket as well.
+func (p *syntaxParser) nodes(c rune) ([]*node, []string, error) {
+	ns := []*node{}
+	cs := []string(nil)
+
+	for {
+		l := p.space()
+
+		if l > 1 && (len(ns) != 0 || len(cs) != 0) {
+			cs = append(cs, "")
+		}
+
+		if p.eof() {
+			if c != 0 {
+				return nil, nil, errors.New("unexpected end of source")
+			}
+
+			return ns, trimBlanks(cs), nil
+		}
+
+		switch r := p.peek(); {
+		case r == commentChar:
+			s := p.comment()
+
+			if l == 0 && len(ns) != 0 && len(cs) == 0 {
+				ns[len(ns)-1].comment = s
+			} else {
+				cs = append(cs, s)
+			}
+		case r == c:
+			p.position++
+			return ns, trimBlanks(cs), nil
+		case strings.ContainsRune(closers, r):
+			return nil, nil, errors.New("unexpected closing bracket")
+		default:
+			n, err := p.node()
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			n.comments = cs
+			cs = nil
+			ns = append(ns, n)
+		}
+	}
+}
+
+func (p *syntaxParser) node() (*node, error) {
+	n := &node{}
+
+	if p.hasPrefix("..") && p.position+2 < len(p.source) &&
+		!strings.ContainsRune(spaceChars+closers+string(commentChar), p.source[p.position+2]) {
+		n.prefix = ".."
+		p.position += 2
+	}
+
+	switch r := p.peek(); {
+	case strings.ContainsRune(openers, r):
+		p.position++
+		ns, cs, err := p.nodes(rune(closers[strings.IndexRune(openers, r)]))
+
+		if err != nil {
+			return nil, err
+		}
+
+		n.text, n.children, n.footer = string(r), ns, cs
+	case r == '"' || r == '`' || p.hasPrefix("$\""):
+		s, err := p.stringLiteral()
+
+		if err != nil {
+			return nil, err
+		}
+
+		n.text = s
+	case r == '\\':
+		p.position++
+		n.text = "\\"
+	default:
+		b := strings.Builder{}
+
+		for !p.eof() {
+			if r := p.peek(); r == '[' && strings.ContainsRune(b.String(), ':') {
+				s, err := p.typeArguments()
+
+				if err != nil {
+					return nil, err
+				}
+
+				b.WriteString(s)
+			} else if strings.ContainsRune(atomEnds, r) {
+				break
+			} else {
+				b.WriteRune(r)
+				p.position++
+			}
+		}
+
+		if b.Len() == 0 {
+			return nil, errors.New("invalid character")
+		}
+
+		n.text = b.String()
+	}
+
+	return n, nil
+}
+
+// typeArguments parses brackets of element types in a type annotation into a
+// string with normalized spaces.
+func (p *syntaxParser) typeArguments() (string, error) {
+	ss := []string{}
+	p.position++
+
+	for {
+		p.space()
+
+		if p.eof() {
+			return "", errors.New("unexpected end of source")
+		} else if p.peek() == ']' {
+			p.position++
+			return "[" + strings.Join(ss, " ") + "]", nil
+		}
+
+		b := strings.Builder{}
+
+		for !p.eof() && !strings.ContainsRune(spaceChars+"]", p.peek()) {
+			if p.peek() == '[' {
+				s, err := p.typeArguments()
+
+				if err != nil {
+					return "", err
+				}
+
+				b.WriteString(s)
+			} else {
+				b.WriteRune(p.peek())
+				p.position++
+			}
+		}
+
+		ss = append(ss, b.String())
+	}
+}
+
+// stringLiteral parses a string literal of any kind into its source text.
+func (p *syntaxParser) stringLiteral() (string, error) {
+	i := p.position
+
+	if err := p.skipString(); err != nil {
+		return "", err
+	}
+
+	return string(p.source[i:p.position]), nil
+}
+
+func (p *syntaxParser) skipString() error {
+	switch {
+	case p.hasPrefix(`"""`):
+		return p.skipMultiLineString()
+	case p.hasPrefix("$\""):
+		p.position++
+		return p.skipQuotedString(true)
+	case p.peek() == '`':
+		p.position++
+
+		for !p.eof() {
+			if p.peek() == '`' {
+				p.position++
+				return nil
+			}
+
+			p.position++
+		}
+
+		return errors.New("unterminated string literal")
+	}
+
+	return p.skipQuotedString(false)
+}
+
+// skipQuotedString skips a string literal in double quotes. Expressions in
+// braces are skipped as well if it is interpolated.
+func (p *syntaxParser) skipQuotedString(interpolated bool) error {
+	p.position++
+
+	for !p.eof() {
+		switch p.peek() {
+		case '\\':
+			p.position += 2
+		case '"':
+			p.position++
+			return nil
+		case '{':
+			if !interpolated {
+				p.position++
+			} else if err := p.skipEmbeddedExpression(); err != nil {
+				return err
+			}
+		default:
+			p.position++
+		}
+	}
+
+	return errors.New("unterminated string literal")
+}
+
+// skipEmbeddedExpression skips an expression in braces in an interpolated
+// string literal.
+func (p *syntaxParser) skipEmbeddedExpression() error {
+	d := 0
+
+	for !p.eof() {
+		switch r := p.peek(); {
+		case r == '"' || r == '`' || p.hasPrefix("$\""):
+			if err := p.skipString(); err != nil {
+				return err
+			}
+
+			continue
+		case r == '{':
+			d++
+		case r == '}':
+			d--
+		}
+
+		p.position++
+
+		if d == 0 {
+			return nil
+		}
+	}
+
+	return errors.New("unterminated string literal")
+}
+
+// skipMultiLineString skips a string literal in triple double quotes whose
+// closing quotes can be followed by quotes in its contents.
+func (p *syntaxParser) skipMultiLineString() error {
+	p.position += 3
+
+	for !p.eof() {
+		if p.hasPrefix(`"""`) {
+			for !p.eof() && p.peek() == '"' {
+				p.position++
+			}
+
+			return nil
+		}
+
+		p.position++
+	}
+
+	return errors.New("unterminated string literal")
+}
+
+func (p *syntaxParser) comment() string {
+	i := p.position
+
+	for !p.eof() && p.peek() != '\n' {
+		p.position++
+	}
+
+	return strings.TrimRight(string(p.source[i:p.position]), spaceChars)
+}
+
+// space skips spaces and returns a number of newlines in them.
+func (p *syntaxParser) space() int {
+	n := 0
+
+	for !p.eof() && strings.ContainsRune(spaceChars, p.peek()) {
+		if p.peek() == '\n' {
+			n++
+		}
+
+		p.position++
+	}
+
+	return n
+}
+
+func (p *syntaxParser) hasPrefix(s string) bool {
+	return strings.HasPrefix(string(p.source[p.position:min(p.position+len(s), len(p.source))]), s)
+}
+
+func (p *syntaxParser) peek() rune {
+	return p.source[p.position]
+}
+
+func (p *syntaxParser) eof() bool {
+	return p.position >= len(p.source)
+}
+
+func trimBlanks(cs []string) []string {
+	for len(cs) != 0 && cs[len(cs)-1] == "" {
+		cs = cs[:len(cs)-1]
+	}
+
+	return cs
+}
+
+func min(i, j int) int {
+	if i < j {
+		return i
+	}
+
+	return j
+}
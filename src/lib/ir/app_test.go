@@ -7,11 +7,13 @@ import (
 	"github.com/cloe-lang/cloe/src/lib/debug"
 )
 
-func TestAppInterpret(t *testing.T) {
-	NewApp(
+func TestAppCompile(t *testing.T) {
+	b := compileBytecode(0, nil, NewApp(
 		core.ToString,
 		NewArguments(
 			[]PositionalArgument{NewPositionalArgument(core.Nil, false)},
 			[]KeywordArgument{NewKeywordArgument("foo", core.Nil), NewKeywordArgument("", core.Nil)}),
-		debug.NewGoInfo(0)).interpret(nil)
+		debug.NewGoInfo(0)))
+
+	b.run(nil)
 }
@@ -0,0 +1,204 @@
+package ir
+
+import (
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+// maxStackRegisters is a maximum number of registers allocated on stack.
+const maxStackRegisters = 32
+
+// bytecode represents a function body compiled into instructions.
+type bytecode struct {
+	instructions []instruction
+	constants    []core.Value
+	apps         []app
+	switches     []switchTable
+	registers    int
+}
+
+type app struct {
+	positionals []int
+	expanded    []bool
+	keywords    []string
+	values      []int
+	info        *debug.Info
+}
+
+type switchTable struct {
+	dict        core.Value
+	cases       []int
+	defaultCase int
+}
+
+type bytecodeCompiler struct {
+	bytecode
+	arity  int
+	locals []int
+}
+
+// compileBytecode compiles a function body in IR into bytecode. Arguments
+// are stored in the first registers and local variables are aliases of
+// operands computing them.
+func compileBytecode(arity int, vars []interface{}, expr interface{}) bytecode {
+	c := bytecodeCompiler{bytecode{registers: arity}, arity, make([]int, 0, len(vars))}
+
+	for _, v := range vars {
+		c.locals = append(c.locals, c.compileExpression(v))
+	}
+
+	c.emit(opReturn, 0, c.compileExpression(expr), 0)
+
+	return c.bytecode
+}
+
+func (c *bytecodeCompiler) compileExpression(expr interface{}) int {
+	switch x := expr.(type) {
+	case int:
+		if x < c.arity {
+			return x
+		}
+
+		return c.locals[x-c.arity]
+	case core.Value:
+		c.constants = append(c.constants, x)
+		return constantOperand(len(c.constants) - 1)
+	case App:
+		return c.compileApp(x)
+	case Switch:
+		return c.compileSwitch(x)
+	}
+
+	panic("Unreachable")
+}
+
+func (c *bytecodeCompiler) compileApp(x App) int {
+	a := app{
+		make([]int, 0, len(x.args.positionals)),
+		make([]bool, 0, len(x.args.positionals)),
+		make([]string, 0, len(x.args.keywords)),
+		make([]int, 0, len(x.args.keywords)),
+		x.info,
+	}
+
+	for _, p := range x.args.positionals {
+		a.positionals = append(a.positionals, c.compileExpression(p.value))
+		a.expanded = append(a.expanded, p.expanded)
+	}
+
+	for _, k := range x.args.keywords {
+		a.keywords = append(a.keywords, k.name)
+		a.values = append(a.values, c.compileExpression(k.value))
+	}
+
+	f := c.compileExpression(x.function)
+	r := c.register()
+
+	c.apps = append(c.apps, a)
+	c.emit(opApp, r, f, len(c.apps)-1)
+
+	return r
+}
+
+func (c *bytecodeCompiler) compileSwitch(x Switch) int {
+	m := c.compileExpression(x.matchedValue)
+	r := c.register()
+	s := len(c.switches)
+
+	c.switches = append(c.switches, switchTable{dict: x.dict})
+	c.emit(opSwitch, 0, m, s)
+
+	cs := make([]int, 0, len(x.caseValues))
+	js := make([]int, 0, len(x.caseValues))
+
+	for _, v := range x.caseValues {
+		cs = append(cs, len(c.instructions))
+		c.emit(opMove, r, c.compileExpression(v), 0)
+		js = append(js, c.emit(opJump, 0, 0, 0))
+	}
+
+	d := len(c.instructions)
+	c.emit(opMove, r, c.compileExpression(x.defaultCase), 0)
+
+	for _, j := range js {
+		c.instructions[j].x = len(c.instructions)
+	}
+
+	c.switches[s].cases = cs
+	c.switches[s].defaultCase = d
+
+	return r
+}
+
+func (c *bytecodeCompiler) register() int {
+	c.registers++
+	return c.registers - 1
+}
+
+func (c *bytecodeCompiler) emit(o opcode, dst, x, y int) int {
+	c.instructions = append(c.instructions, instruction{o, dst, x, y})
+	return len(c.instructions) - 1
+}
+
+// run runs bytecode with arguments and returns a result.
+func (b *bytecode) run(args []core.Value) core.Value {
+	var buf [maxStackRegisters]core.Value
+	rs := buf[:0]
+
+	if b.registers > maxStackRegisters {
+		rs = make([]core.Value, 0, b.registers)
+	}
+
+	rs = append(rs[:0], args...)
+	rs = rs[:b.registers]
+
+	for pc := 0; ; pc++ {
+		i := &b.instructions[pc]
+
+		switch i.opcode {
+		case opApp:
+			rs[i.dst] = b.app(rs, b.operand(rs, i.x), &b.apps[i.y])
+		case opSwitch:
+			s := &b.switches[i.y]
+			v := core.EvalPure(core.PApp(core.Index, s.dict, b.operand(rs, i.x)))
+
+			if n, ok := v.(*core.NumberType); ok {
+				pc = s.cases[int(*n)] - 1
+			} else {
+				pc = s.defaultCase - 1
+			}
+		case opMove:
+			rs[i.dst] = b.operand(rs, i.x)
+		case opJump:
+			pc = i.x - 1
+		case opReturn:
+			return b.operand(rs, i.x)
+		default:
+			panic("Unreachable")
+		}
+	}
+}
+
+func (b *bytecode) app(rs []core.Value, f core.Value, a *app) core.Value {
+	ps := make([]core.PositionalArgument, 0, len(a.positionals))
+
+	for i, o := range a.positionals {
+		ps = append(ps, core.NewPositionalArgument(b.operand(rs, o), a.expanded[i]))
+	}
+
+	ks := make([]core.KeywordArgument, 0, len(a.keywords))
+
+	for i, n := range a.keywords {
+		ks = append(ks, core.NewKeywordArgument(n, b.operand(rs, a.values[i])))
+	}
+
+	return core.AppWithInfo(f, core.NewArguments(ps, ks), a.info)
+}
+
+func (b *bytecode) operand(rs []core.Value, o int) core.Value {
+	if o < 0 {
+		return b.constants[-o-1]
+	}
+
+	return rs[o]
+}
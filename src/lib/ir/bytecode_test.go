@@ -0,0 +1,69 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileBytecode(t *testing.T) {
+	b := compileBytecode(
+		2,
+		[]interface{}{newAppWithDummyInfo(0, newPositionalArguments(1, core.NewNumber(1)))},
+		newAppWithDummyInfo(0, newPositionalArguments(2, 2)))
+
+	assert.Equal(t, []instruction{
+		{opApp, 2, 0, 0},
+		{opApp, 3, 0, 1},
+		{opReturn, 0, 3, 0},
+	}, b.instructions)
+	assert.Equal(t, []core.Value{core.NewNumber(1)}, b.constants)
+	assert.Equal(t, []int{1, constantOperand(0)}, b.apps[0].positionals)
+	assert.Equal(t, []int{2, 2}, b.apps[1].positionals)
+	assert.Equal(t, 4, b.registers)
+}
+
+func TestCompileBytecodeWithSwitch(t *testing.T) {
+	b := compileBytecode(
+		1,
+		nil,
+		NewSwitch(0, []Case{
+			NewCase(core.NewString("foo"), core.NewNumber(42)),
+			NewCase(core.True, newAppWithDummyInfo(core.ToString, newPositionalArguments(0))),
+		}, 0))
+
+	assert.Equal(t, []instruction{
+		{opSwitch, 0, 0, 0},
+		{opMove, 1, constantOperand(0), 0},
+		{opJump, 0, 7, 0},
+		{opApp, 2, constantOperand(1), 0},
+		{opMove, 1, 2, 0},
+		{opJump, 0, 7, 0},
+		{opMove, 1, 0, 0},
+		{opReturn, 0, 1, 0},
+	}, b.instructions)
+	assert.Equal(t, []int{1, 3}, b.switches[0].cases)
+	assert.Equal(t, 6, b.switches[0].defaultCase)
+}
+
+func TestBytecodeRun(t *testing.T) {
+	b := compileBytecode(
+		2,
+		[]interface{}{newAppWithDummyInfo(core.Add, newPositionalArguments(0, 1))},
+		NewSwitch(2, []Case{
+			NewCase(core.NewNumber(3), core.NewString("three")),
+			NewCase(core.NewNumber(4), newAppWithDummyInfo(core.ToString, newPositionalArguments(2))),
+		}, core.Nil))
+
+	for _, c := range []struct {
+		arguments []core.Value
+		answer    core.Value
+	}{
+		{[]core.Value{core.NewNumber(1), core.NewNumber(2)}, core.NewString("three")},
+		{[]core.Value{core.NewNumber(2), core.NewNumber(2)}, core.NewString("4")},
+		{[]core.Value{core.NewNumber(2), core.NewNumber(3)}, core.Nil},
+	} {
+		assert.Equal(t, c.answer, core.EvalPure(b.run(c.arguments)))
+	}
+}
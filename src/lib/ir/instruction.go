@@ -0,0 +1,34 @@
+package ir
+
+type opcode uint8
+
+const (
+	// opApp creates a thunk of an application and stores it into a register
+	// dst. An operand x is a function and y is an index of an application.
+	opApp opcode = iota
+
+	// opSwitch evaluates an operand x and jumps to a case of a switch
+	// expression whose index is y.
+	opSwitch
+
+	// opMove copies an operand x into a register dst.
+	opMove
+
+	// opJump jumps to an address x.
+	opJump
+
+	// opReturn returns an operand x.
+	opReturn
+)
+
+// instruction represents an instruction of bytecode. Operands are indices of
+// registers if they are non-negative or ones of constants otherwise.
+type instruction struct {
+	opcode opcode
+	dst    int
+	x, y   int
+}
+
+func constantOperand(i int) int {
+	return -i - 1
+}
@@ -1,7 +1,5 @@
 package ir
 
-import "github.com/cloe-lang/cloe/src/lib/core"
-
 // KeywordArgument represents a keyword argument passed to a function.
 type KeywordArgument struct {
 	name  string
@@ -12,7 +10,3 @@ type KeywordArgument struct {
 func NewKeywordArgument(n string, v interface{}) KeywordArgument {
 	return KeywordArgument{n, v}
 }
-
-func (k KeywordArgument) interpret(args []core.Value) core.KeywordArgument {
-	return core.NewKeywordArgument(k.name, interpretExpression(args, k.value))
-}
@@ -4,12 +4,22 @@ import (
 	"testing"
 
 	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewKeywordArgument(t *testing.T) {
 	NewKeywordArgument("foo", 0)
 }
 
-func TestKeywordArgumentInterpret(t *testing.T) {
-	NewKeywordArgument("foo", 0).interpret([]core.Value{core.NewNumber(123)})
+func TestKeywordArgumentInFunction(t *testing.T) {
+	f := CompileFunction(
+		core.NewSignature([]string{"x"}, "", nil, ""),
+		nil,
+		newAppWithDummyInfo(
+			core.NewLazyFunction(
+				core.NewSignature(nil, "", []core.OptionalParameter{core.NewOptionalParameter("foo", core.Nil)}, ""),
+				func(vs ...core.Value) core.Value { return vs[0] }),
+			NewArguments(nil, []KeywordArgument{NewKeywordArgument("foo", 0)})))
+
+	assert.Equal(t, core.NewNumber(123), core.EvalPure(core.PApp(f, core.NewNumber(123))))
 }
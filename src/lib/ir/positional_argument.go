@@ -1,7 +1,5 @@
 package ir
 
-import "github.com/cloe-lang/cloe/src/lib/core"
-
 // PositionalArgument represents a positional argument passed to a function.
 // It can be a list value and expanded into multiple arguments.
 type PositionalArgument struct {
@@ -13,7 +11,3 @@ type PositionalArgument struct {
 func NewPositionalArgument(v interface{}, expanded bool) PositionalArgument {
 	return PositionalArgument{v, expanded}
 }
-
-func (p PositionalArgument) interpret(args []core.Value) core.PositionalArgument {
-	return core.NewPositionalArgument(interpretExpression(args, p.value), p.expanded)
-}
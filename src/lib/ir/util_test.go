@@ -61,10 +61,10 @@ func newAppWithDummyInfo(f interface{}, args Arguments) App {
 	return NewApp(f, args, debug.NewGoInfo(0))
 }
 
-func TestInterpretExpressionFail(t *testing.T) {
+func TestCompileFunctionWithInvalidExpression(t *testing.T) {
 	defer func() {
 		assert.NotNil(t, recover())
 	}()
 
-	interpretExpression(nil, "foo")
+	CompileFunction(core.NewSignature(nil, "", nil, ""), nil, "foo")
 }
@@ -0,0 +1,461 @@
+package lsp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/compile"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/modules"
+	"github.com/cloe-lang/cloe/src/lib/parse"
+)
+
+const (
+	diagnosticSource = "cloe"
+	nonIdentifiers   = " \t\n\r;()[]{}\"\\$`"
+)
+
+// document is a source file opened in an editor.
+type document struct {
+	uri, path, text string
+	module          []interface{} // nil if the text cannot be parsed
+}
+
+func newDocument(uri, text string) *document {
+	p := uriToPath(uri)
+	m, err := parse.MainModule(p, text)
+
+	if err != nil {
+		m = nil
+	}
+
+	return &document{uri, p, text, m}
+}
+
+// definition is a function or variable defined by a let statement.
+type definition struct {
+	name      string
+	signature *ast.Signature // nil for variables
+	children  []definition
+	info      *debug.Info
+}
+
+func definitions(m []interface{}) []definition {
+	ds := []definition{}
+
+	for _, s := range m {
+		switch x := s.(type) {
+		case ast.DefFunction:
+			ds = append(ds, functionDefinition(x))
+		case ast.MutualRecursion:
+			for _, f := range x.DefFunctions() {
+				ds = append(ds, functionDefinition(f))
+			}
+		case ast.LetVar:
+			ds = append(ds, definition{x.Name(), nil, nil, x.DebugInfo()})
+		}
+	}
+
+	return ds
+}
+
+// exportedDefinitions lists top-level definitions exported by a module.
+func exportedDefinitions(m []interface{}) []definition {
+	ds := []definition{}
+
+	for _, d := range definitions(m) {
+		if compile.IsExported(m, d.name) {
+			ds = append(ds, d)
+		}
+	}
+
+	return ds
+}
+
+func functionDefinition(f ast.DefFunction) definition {
+	s := f.Signature()
+	return definition{f.Name(), &s, definitions(f.Lets()), f.DebugInfo()}
+}
+
+func (d definition) String() string {
+	if d.signature == nil {
+		return fmt.Sprintf("(let %s)", d.name)
+	} else if s := d.signature.String(); s != "" {
+		return fmt.Sprintf("(def (%s %s))", d.name, s)
+	}
+
+	return fmt.Sprintf("(def (%s))", d.name)
+}
+
+func imports(m []interface{}) []ast.Import {
+	is := []ast.Import{}
+
+	for _, s := range m {
+		if i, ok := s.(ast.Import); ok {
+			is = append(is, i)
+		}
+	}
+
+	return is
+}
+
+// diagnostics compiles a document and converts errors found into diagnostics.
+func (d *document) diagnostics() []diagnostic {
+	err := d.compile()
+
+	if err == nil {
+		return []diagnostic{}
+	}
+
+	es, ok := err.(debug.Errors)
+
+	if !ok {
+		es = debug.Errors{err}
+	}
+
+	ds := make([]diagnostic, 0, len(es))
+
+	for _, e := range es {
+		ds = append(ds, d.diagnostic(e))
+	}
+
+	return ds
+}
+
+func (d *document) compile() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	_, err = compile.CompileSource(d.path, d.text)
+	return err
+}
+
+func (d *document) diagnostic(err error) diagnostic {
+	r := textRange{}
+	m := strings.TrimSpace(err.Error())
+
+	if e, ok := err.(*debug.Error); ok {
+		if i := e.Info(); i != nil && i.File() == d.path {
+			r = lineRange(i)
+			m = e.Name() + ": " + e.Message()
+		}
+	}
+
+	return diagnostic{r, diagnosticSeverityError, diagnosticSource, m}
+}
+
+// definition finds a definition of a name referred at a position. It returns
+// a definition and a URI of a document where it is found.
+func (d *document) definition(p position) (definition, string, bool) {
+	n := d.identifier(p)
+
+	if n == "" {
+		return definition{}, "", false
+	}
+
+	if f, ok := d.enclosingFunction(p.Line); ok {
+		if x, ok := findDefinition(f.children, n); ok {
+			return x, d.uri, true
+		} else if _, ok := f.signature.NameToIndex()[n]; ok {
+			return definition{n, nil, nil, f.info}, d.uri, true
+		}
+	}
+
+	if x, ok := findDefinition(definitions(d.module), n); ok {
+		return x, d.uri, true
+	}
+
+	for _, i := range imports(d.module) {
+		f, m, ok := d.localModule(i)
+
+		if !ok {
+			continue
+		}
+
+		for _, x := range exportedDefinitions(m) {
+			if l, ok := i.LocalName(x.name); ok && l == n {
+				return x, pathToURI(f), true
+			}
+		}
+	}
+
+	return definition{}, "", false
+}
+
+// enclosingFunction finds a top-level function definition which contains a
+// line. It assumes that each statement lasts until the next one begins.
+func (d *document) enclosingFunction(l int) (definition, bool) {
+	x, ok := definition{}, false
+
+	for _, s := range d.module {
+		ss := []interface{}{s}
+
+		if mr, ok := s.(ast.MutualRecursion); ok {
+			ss = ss[:0]
+
+			for _, f := range mr.DefFunctions() {
+				ss = append(ss, f)
+			}
+		}
+
+		for _, s := range ss {
+			i, isInfo := s.(interface {
+				DebugInfo() *debug.Info
+			})
+
+			if !isInfo || i.DebugInfo() == nil || i.DebugInfo().LineNumber()-1 > l {
+				continue
+			}
+
+			f, isFunction := s.(ast.DefFunction)
+			x, ok = functionDefinition(f), isFunction
+		}
+	}
+
+	return x, ok
+}
+
+func findDefinition(ds []definition, n string) (definition, bool) {
+	for _, d := range ds {
+		if d.name == n {
+			return d, true
+		}
+	}
+
+	for _, d := range ds {
+		if x, ok := findDefinition(d.children, n); ok {
+			return x, true
+		}
+	}
+
+	return definition{}, false
+}
+
+// localModule parses a local module imported by a document.
+func (d *document) localModule(i ast.Import) (string, []interface{}, bool) {
+	if _, ok := modules.Modules[i.Path()]; ok {
+		return "", nil, false
+	}
+
+	f, err := compile.ModuleFile(i.Path(), filepath.ToSlash(path.Dir(d.path)))
+
+	if err != nil {
+		return "", nil, false
+	}
+
+	bs, err := ioutil.ReadFile(f)
+
+	if err != nil {
+		return "", nil, false
+	}
+
+	m, err := parse.SubModule(f, string(bs))
+
+	if err != nil {
+		return "", nil, false
+	}
+
+	return f, m, true
+}
+
+// hover describes a name referred at a position.
+func (d *document) hover(p position) (string, bool) {
+	if x, _, ok := d.definition(p); ok {
+		return x.String(), true
+	}
+
+	n := d.identifier(p)
+
+	for _, s := range compile.BuiltinNames() {
+		if s == n {
+			return fmt.Sprintf("%s (built-in)", n), true
+		}
+	}
+
+	for _, i := range imports(d.module) {
+		m, ok := modules.Modules[i.Path()]
+
+		if !ok {
+			continue
+		}
+
+		for k := range m {
+			if l, ok := i.LocalName(k); ok && l == n {
+				return fmt.Sprintf("%s (built-in module %q)", n, i.Path()), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// completions lists names available in a document.
+func (d *document) completions() []completionItem {
+	cs := []completionItem{}
+
+	for _, s := range compile.BuiltinNames() {
+		cs = append(cs, completionItem{s, completionItemKindFunction, "built-in"})
+	}
+
+	for _, x := range definitions(d.module) {
+		cs = append(cs, definitionCompletion(x, x.name))
+	}
+
+	for _, i := range imports(d.module) {
+		if i.Data() {
+			cs = append(cs, completionItem{i.Prefix(), completionItemKindVariable, i.Path()})
+			continue
+		} else if i.Names() == nil && i.Prefix() != "" {
+			cs = append(cs, completionItem{i.Prefix(), completionItemKindModule, i.Path()})
+		}
+
+		if m, ok := modules.Modules[i.Path()]; ok {
+			for k := range m {
+				if l, ok := i.LocalName(k); ok {
+					cs = append(cs, completionItem{l, completionItemKindFunction, i.Path()})
+				}
+			}
+		} else if _, m, ok := d.localModule(i); ok {
+			for _, x := range exportedDefinitions(m) {
+				if l, ok := i.LocalName(x.name); ok {
+					cs = append(cs, definitionCompletion(x, l))
+				}
+			}
+		}
+	}
+
+	return cs
+}
+
+// definitionCompletion creates a completion item of a definition with its
+// name in a document.
+func definitionCompletion(d definition, n string) completionItem {
+	if d.signature == nil {
+		return completionItem{n, completionItemKindVariable, d.String()}
+	}
+
+	return completionItem{n, completionItemKindFunction, d.String()}
+}
+
+// symbols lists definitions and imports in a document.
+func (d *document) symbols() []documentSymbol {
+	ss := []documentSymbol{}
+
+	for _, i := range imports(d.module) {
+		r := lineRange(i.DebugInfo())
+		ss = append(ss, documentSymbol{i.Path(), i.Prefix(), symbolKindModule, r, r, nil})
+	}
+
+	return append(ss, definitionSymbols(definitions(d.module))...)
+}
+
+func definitionSymbols(ds []definition) []documentSymbol {
+	ss := make([]documentSymbol, 0, len(ds))
+
+	for _, d := range ds {
+		k := symbolKindVariable
+
+		if d.signature != nil {
+			k = symbolKindFunction
+		}
+
+		ss = append(ss, documentSymbol{
+			d.name,
+			d.String(),
+			k,
+			lineRange(d.info),
+			nameRange(d.info, d.name),
+			definitionSymbols(d.children),
+		})
+	}
+
+	return ss
+}
+
+// identifier finds a name at a position in a document.
+func (d *document) identifier(p position) string {
+	ls := strings.Split(d.text, "\n")
+
+	if p.Line < 0 || p.Line >= len(ls) {
+		return ""
+	}
+
+	rs := []rune(ls[p.Line])
+	s := p.Character
+
+	if s > len(rs) {
+		s = len(rs)
+	}
+
+	e := s
+
+	for s > 0 && !strings.ContainsRune(nonIdentifiers, rs[s-1]) {
+		s--
+	}
+
+	for e < len(rs) && !strings.ContainsRune(nonIdentifiers, rs[e]) {
+		e++
+	}
+
+	return strings.TrimLeft(string(rs[s:e]), ".")
+}
+
+// lineRange converts debug information into a range from its position to the
+// end of its line.
+func lineRange(i *debug.Info) textRange {
+	if i == nil {
+		return textRange{}
+	}
+
+	l := i.LineNumber() - 1
+	c := i.LinePosition() - 1
+
+	if c < 0 {
+		c = 0
+	}
+
+	return textRange{position{l, c}, position{l, len([]rune(i.Source()))}}
+}
+
+// nameRange finds a range of a name in a line of debug information.
+func nameRange(i *debug.Info, n string) textRange {
+	if i == nil {
+		return textRange{}
+	}
+
+	r := lineRange(i)
+	rs := []rune(i.Source())
+	m := len([]rune(n))
+
+	for c := r.Start.Character; c+m <= len(rs); c++ {
+		if string(rs[c:c+m]) == n &&
+			(c == 0 || strings.ContainsRune(nonIdentifiers, rs[c-1])) &&
+			(c+m == len(rs) || strings.ContainsRune(nonIdentifiers, rs[c+m])) {
+			return textRange{position{r.Start.Line, c}, position{r.Start.Line, c + m}}
+		}
+	}
+
+	return r
+}
+
+func uriToPath(s string) string {
+	u, err := url.Parse(s)
+
+	if err != nil || u.Scheme != "file" {
+		return s
+	}
+
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(p string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(p)}).String()
+}
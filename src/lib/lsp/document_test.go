@@ -0,0 +1,152 @@
+package lsp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSource = `(import "re")
+(import "./foo")
+
+(let x 42)
+
+(def (f y . z 1)
+  (def (g w) (+ w y))
+  (g x))
+
+(print (f x))
+(print (foo.bar x))
+`
+
+func TestDocumentDiagnostics(t *testing.T) {
+	assert.Equal(t, 0, len(newDocument("file:///main.cloe", `(print 42)`).diagnostics()))
+
+	ds := newDocument("file:///main.cloe", "(let x 42)\n(print (f x))").diagnostics()
+
+	assert.Equal(t, 1, len(ds))
+	assert.Equal(t, 1, ds[0].Range.Start.Line)
+	assert.Contains(t, ds[0].Message, "NameError")
+
+	ds = newDocument("file:///main.cloe", "(print 42").diagnostics()
+
+	assert.Equal(t, 1, len(ds))
+	assert.Contains(t, ds[0].Message, "SyntaxError")
+}
+
+func TestDocumentDefinition(t *testing.T) {
+	d, p := newTestDocument(t)
+	defer os.RemoveAll(p)
+
+	for _, c := range []struct {
+		position position
+		line     int
+		file     string
+	}{
+		{position{7, 3}, 6, "main.cloe"},
+		{position{7, 5}, 3, "main.cloe"},
+		{position{6, 18}, 5, "main.cloe"},
+		{position{9, 8}, 5, "main.cloe"},
+		{position{10, 12}, 0, "foo.cloe"},
+	} {
+		x, u, ok := d.definition(c.position)
+
+		assert.True(t, ok)
+		assert.Equal(t, c.line, x.info.LineNumber()-1)
+		assert.Equal(t, c.file, filepath.Base(uriToPath(u)))
+	}
+
+	for _, p := range []position{{0, 0}, {2, 0}, {9, 2}, {42, 0}} {
+		_, _, ok := d.definition(p)
+		assert.False(t, ok)
+	}
+}
+
+func TestDocumentHover(t *testing.T) {
+	d, p := newTestDocument(t)
+	defer os.RemoveAll(p)
+
+	for _, c := range []struct {
+		position position
+		hover    string
+	}{
+		{position{9, 8}, "(def (f y . z 1))"},
+		{position{9, 11}, "(let x)"},
+		{position{9, 2}, "print (built-in)"},
+	} {
+		s, ok := d.hover(c.position)
+
+		assert.True(t, ok)
+		assert.Equal(t, c.hover, s)
+	}
+
+	_, ok := d.hover(position{2, 0})
+	assert.False(t, ok)
+}
+
+func TestDocumentCompletions(t *testing.T) {
+	d, p := newTestDocument(t)
+	defer os.RemoveAll(p)
+
+	ss := []string{}
+
+	for _, c := range d.completions() {
+		ss = append(ss, c.Label)
+	}
+
+	for _, s := range []string{"+", "map", "x", "f", "re.match", "foo", "foo.bar"} {
+		assert.Contains(t, ss, s)
+	}
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	d, p := newTestDocument(t)
+	defer os.RemoveAll(p)
+
+	ss := d.symbols()
+
+	assert.Equal(t, 4, len(ss))
+	assert.Equal(t, "f", ss[3].Name)
+	assert.Equal(t, symbolKindFunction, ss[3].Kind)
+	assert.Equal(t, position{5, 6}, ss[3].SelectionRange.Start)
+	assert.Equal(t, "g", ss[3].Children[0].Name)
+}
+
+func TestDocumentIdentifier(t *testing.T) {
+	d := newDocument("file:///main.cloe", "(foo.bar ..baz)\n(f `qux`)\n")
+
+	for _, c := range []struct {
+		position   position
+		identifier string
+	}{
+		{position{0, 0}, ""},
+		{position{0, 1}, "foo.bar"},
+		{position{0, 8}, "foo.bar"},
+		{position{0, 12}, "baz"},
+		{position{0, 42}, ""},
+		{position{1, 0}, ""},
+		{position{1, 4}, "qux"},
+		{position{2, 0}, ""},
+		{position{3, 0}, ""},
+	} {
+		assert.Equal(t, c.identifier, d.identifier(c.position))
+	}
+}
+
+func TestURIToPath(t *testing.T) {
+	assert.Equal(t, filepath.FromSlash("/foo/bar.cloe"), uriToPath("file:///foo/bar.cloe"))
+	assert.Equal(t, "untitled:foo", uriToPath("untitled:foo"))
+	assert.Equal(t, "file:///foo/bar.cloe", pathToURI(filepath.FromSlash("/foo/bar.cloe")))
+}
+
+func newTestDocument(t *testing.T) (*document, string) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(d, "foo.cloe"), []byte("(def (bar x) x)"), 0644))
+
+	return newDocument(pathToURI(filepath.Join(d, "main.cloe")), testSource), d
+}
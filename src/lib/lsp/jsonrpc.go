@@ -0,0 +1,115 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	jsonrpcVersion      = "2.0"
+	contentLengthHeader = "content-length:"
+
+	methodNotFound = -32601
+	invalidParams  = -32602
+)
+
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Error   *responseError   `json:"error"`
+}
+
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newResponseError(c int, m string, xs ...interface{}) *responseError {
+	return &responseError{c, fmt.Sprintf(m, xs...)}
+}
+
+func (e *responseError) Error() string {
+	return e.Message
+}
+
+func readMessage(r *bufio.Reader) (message, error) {
+	bs, err := readContent(r)
+
+	if err != nil {
+		return message{}, err
+	}
+
+	m := message{}
+	err = json.Unmarshal(bs, &m)
+	return m, err
+}
+
+// readContent reads a content part of a message after its header part.
+func readContent(r *bufio.Reader) ([]byte, error) {
+	n := -1
+
+	for {
+		l, err := r.ReadString('\n')
+
+		if err != nil {
+			return nil, err
+		}
+
+		l = strings.TrimSpace(l)
+
+		if l == "" {
+			break
+		} else if !strings.HasPrefix(strings.ToLower(l), contentLengthHeader) {
+			continue
+		}
+
+		n, err = strconv.Atoi(strings.TrimSpace(l[len(contentLengthHeader):]))
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if n < 0 {
+		return nil, errors.New("Content-Length header not found")
+	}
+
+	bs := make([]byte, n)
+	_, err := io.ReadFull(r, bs)
+	return bs, err
+}
+
+func writeMessage(w io.Writer, x interface{}) error {
+	bs, err := json.Marshal(x)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(bs), bs)
+	return err
+}
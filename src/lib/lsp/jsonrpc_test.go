@@ -0,0 +1,41 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadMessage(t *testing.T) {
+	s := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+
+	m, err := readMessage(bufio.NewReader(strings.NewReader(
+		"Content-Length: 58\r\nContent-Type: application/vscode-jsonrpc\r\n\r\n" + s)))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "initialize", m.Method)
+	assert.Equal(t, "1", string(*m.ID))
+}
+
+func TestReadMessageError(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"\r\n{}",
+		"Content-Length: foo\r\n\r\n{}",
+		"Content-Length: 42\r\n\r\n{}",
+		"Content-Length: 2\r\n\r\n[]",
+	} {
+		_, err := readMessage(bufio.NewReader(strings.NewReader(s)))
+		assert.NotNil(t, err)
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	b := &bytes.Buffer{}
+
+	assert.Nil(t, writeMessage(b, notification{jsonrpcVersion, "foo", nil}))
+	assert.Equal(t, "Content-Length: 46\r\n\r\n{\"jsonrpc\":\"2.0\",\"method\":\"foo\",\"params\":null}", b.String())
+}
@@ -0,0 +1,229 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+type handler func(*server, json.RawMessage) (interface{}, error)
+
+var handlers = map[string]handler{
+	"initialize":                  (*server).initialize,
+	"shutdown":                    (*server).shutdown,
+	"textDocument/didOpen":        (*server).didOpen,
+	"textDocument/didChange":      (*server).didChange,
+	"textDocument/didClose":       (*server).didClose,
+	"textDocument/definition":     (*server).definition,
+	"textDocument/hover":          (*server).hover,
+	"textDocument/completion":     (*server).completion,
+	"textDocument/documentSymbol": (*server).documentSymbol,
+}
+
+type server struct {
+	reader    *bufio.Reader
+	writer    io.Writer
+	documents map[string]*document
+}
+
+// Serve runs a language server which reads messages from r and writes ones
+// into w until it receives an exit notification or r is closed.
+func Serve(r io.Reader, w io.Writer) error {
+	s := &server{bufio.NewReader(r), w, map[string]*document{}}
+
+	for {
+		m, err := readMessage(s.reader)
+
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		} else if m.Method == "exit" {
+			return nil
+		}
+
+		if err := s.handle(m); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) handle(m message) error {
+	h, ok := handlers[m.Method]
+
+	if !ok {
+		if m.ID == nil {
+			return nil
+		}
+
+		return s.writeError(m, newResponseError(methodNotFound, "method %s not found", m.Method))
+	}
+
+	x, err := h(s, m.Params)
+
+	if e, ok := err.(*responseError); ok {
+		if m.ID == nil {
+			return nil
+		}
+
+		return s.writeError(m, e)
+	} else if err != nil {
+		return err
+	} else if m.ID == nil {
+		return nil
+	}
+
+	return writeMessage(s.writer, response{jsonrpcVersion, m.ID, x})
+}
+
+func (s *server) writeError(m message, e *responseError) error {
+	return writeMessage(s.writer, errorResponse{jsonrpcVersion, m.ID, e})
+}
+
+func (s *server) notify(m string, x interface{}) error {
+	return writeMessage(s.writer, notification{jsonrpcVersion, m, x})
+}
+
+func (s *server) initialize(json.RawMessage) (interface{}, error) {
+	return initializeResult{serverCapabilities{
+		TextDocumentSync:       textDocumentSyncFull,
+		DefinitionProvider:     true,
+		HoverProvider:          true,
+		CompletionProvider:     completionOptions{[]string{"."}},
+		DocumentSymbolProvider: true,
+	}}, nil
+}
+
+func (s *server) shutdown(json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+
+func (s *server) didOpen(bs json.RawMessage) (interface{}, error) {
+	p := didOpenTextDocumentParams{}
+
+	if err := unmarshalParams(bs, &p); err != nil {
+		return nil, err
+	}
+
+	return nil, s.updateDocument(p.TextDocument.URI, p.TextDocument.Text)
+}
+
+func (s *server) didChange(bs json.RawMessage) (interface{}, error) {
+	p := didChangeTextDocumentParams{}
+
+	if err := unmarshalParams(bs, &p); err != nil {
+		return nil, err
+	} else if len(p.ContentChanges) == 0 {
+		return nil, nil
+	}
+
+	return nil, s.updateDocument(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+}
+
+func (s *server) didClose(bs json.RawMessage) (interface{}, error) {
+	p := didCloseTextDocumentParams{}
+
+	if err := unmarshalParams(bs, &p); err != nil {
+		return nil, err
+	}
+
+	delete(s.documents, p.TextDocument.URI)
+
+	return nil, s.notify(
+		"textDocument/publishDiagnostics",
+		publishDiagnosticsParams{p.TextDocument.URI, []diagnostic{}})
+}
+
+func (s *server) updateDocument(u, t string) error {
+	d := newDocument(u, t)
+	s.documents[u] = d
+
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{u, d.diagnostics()})
+}
+
+func (s *server) definition(bs json.RawMessage) (interface{}, error) {
+	d, p, err := s.documentPosition(bs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	x, u, ok := d.definition(p)
+
+	if !ok {
+		return nil, nil
+	}
+
+	return location{u, nameRange(x.info, x.name)}, nil
+}
+
+func (s *server) hover(bs json.RawMessage) (interface{}, error) {
+	d, p, err := s.documentPosition(bs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	h, ok := d.hover(p)
+
+	if !ok {
+		return nil, nil
+	}
+
+	return hover{markupContent{"markdown", "```cloe\n" + h + "\n```"}}, nil
+}
+
+func (s *server) completion(bs json.RawMessage) (interface{}, error) {
+	d, _, err := s.documentPosition(bs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.completions(), nil
+}
+
+func (s *server) documentSymbol(bs json.RawMessage) (interface{}, error) {
+	p := documentSymbolParams{}
+
+	if err := unmarshalParams(bs, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d.symbols(), nil
+}
+
+func (s *server) documentPosition(bs json.RawMessage) (*document, position, error) {
+	p := textDocumentPositionParams{}
+
+	if err := unmarshalParams(bs, &p); err != nil {
+		return nil, position{}, err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+	return d, p.Position, err
+}
+
+func (s *server) document(u string) (*document, error) {
+	d, ok := s.documents[u]
+
+	if !ok {
+		return nil, newResponseError(invalidParams, "document %s is not opened", u)
+	}
+
+	return d, nil
+}
+
+func unmarshalParams(bs json.RawMessage, x interface{}) error {
+	if err := json.Unmarshal(bs, x); err != nil {
+		return newResponseError(invalidParams, "%v", err)
+	}
+
+	return nil
+}
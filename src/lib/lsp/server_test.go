@@ -0,0 +1,94 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe(t *testing.T) {
+	ms := serve(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///main.cloe","languageId":"cloe","version":1,"text":"(def (f x) x)\n(print (g 42))"}}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///main.cloe"},"contentChanges":[{"text":"(def (f x) x)\n(print (f 42))"}]}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/definition","params":{"textDocument":{"uri":"file:///main.cloe"},"position":{"line":1,"character":8}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///main.cloe"},"position":{"line":1,"character":8}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"textDocument/completion","params":{"textDocument":{"uri":"file:///main.cloe"},"position":{"line":1,"character":8}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"textDocument/documentSymbol","params":{"textDocument":{"uri":"file:///main.cloe"}}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didClose","params":{"textDocument":{"uri":"file:///main.cloe"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+		`{"jsonrpc":"2.0","id":7,"method":"shutdown"}`)
+
+	assert.Equal(t, 9, len(ms))
+
+	assert.True(t, ms[0]["result"].(map[string]interface{})["capabilities"].(map[string]interface{})["hoverProvider"].(bool))
+
+	for i, n := range []int{1, 0} {
+		assert.Equal(t, "textDocument/publishDiagnostics", ms[i+1]["method"])
+		assert.Equal(t, n, len(ms[i+1]["params"].(map[string]interface{})["diagnostics"].([]interface{})))
+	}
+
+	assert.Equal(t, "file:///main.cloe", ms[3]["result"].(map[string]interface{})["uri"])
+	assert.Contains(t, ms[4]["result"].(map[string]interface{})["contents"].(map[string]interface{})["value"], "(def (f x))")
+	assert.NotZero(t, len(ms[5]["result"].([]interface{})))
+	assert.Equal(t, "f", ms[6]["result"].([]interface{})[0].(map[string]interface{})["name"])
+	assert.Equal(t, "textDocument/publishDiagnostics", ms[7]["method"])
+	assert.Nil(t, ms[8]["result"])
+	assert.Contains(t, ms[8], "result")
+}
+
+func TestServeWithInvalidRequests(t *testing.T) {
+	ms := serve(t,
+		`{"jsonrpc":"2.0","id":1,"method":"foo"}`,
+		`{"jsonrpc":"2.0","method":"foo"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///main.cloe"},"position":{"line":0,"character":0}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"textDocument/hover","params":42}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":42}`)
+
+	assert.Equal(t, 3, len(ms))
+
+	for i, c := range []int{methodNotFound, invalidParams, invalidParams} {
+		assert.Equal(t, float64(c), ms[i]["error"].(map[string]interface{})["code"])
+		assert.NotContains(t, ms[i], "result")
+	}
+}
+
+func TestServeWithBrokenMessage(t *testing.T) {
+	assert.NotNil(t, Serve(strings.NewReader("Content-Length: 2\r\n\r\n[]"), &bytes.Buffer{}))
+}
+
+func serve(t *testing.T, ss ...string) []map[string]interface{} {
+	r, w := &bytes.Buffer{}, &bytes.Buffer{}
+
+	for _, s := range ss {
+		assert.Nil(t, writeMessage(r, json.RawMessage(s)))
+	}
+
+	assert.Nil(t, Serve(r, w))
+
+	ms := []map[string]interface{}{}
+	b := bufio.NewReader(w)
+
+	for {
+		_, err := b.Peek(1)
+
+		if err == io.EOF {
+			return ms
+		}
+
+		bs, err := readContent(b)
+		assert.Nil(t, err)
+
+		m := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(bs, &m))
+
+		ms = append(ms, m)
+	}
+}
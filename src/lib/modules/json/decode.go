@@ -14,10 +14,11 @@ var decode = core.NewLazyFunction(
 			return err
 		}
 
-		return decodeString(string(s))
+		return DecodeString(string(s))
 	})
 
-func decodeString(s string) core.Value {
+// DecodeString decodes a JSON string into a value.
+func DecodeString(s string) core.Value {
 	j, err := gabs.ParseJSON([]byte(s))
 
 	if err != nil {
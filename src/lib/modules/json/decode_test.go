@@ -33,7 +33,7 @@ func TestDecodeWithNonString(t *testing.T) {
 
 func TestDecodeString(t *testing.T) {
 	for _, s := range jsons {
-		t.Log(core.EvalPure(core.PApp(core.ToString, decodeString(s))))
+		t.Log(core.EvalPure(core.PApp(core.ToString, DecodeString(s))))
 	}
 }
 
@@ -45,7 +45,7 @@ func TestDecodeStringWithInvalidJSON(t *testing.T) {
 		`nul`,
 		`nil`,
 	} {
-		_, ok := core.EvalPure(decodeString(s)).(*core.ErrorType)
+		_, ok := core.EvalPure(DecodeString(s)).(*core.ErrorType)
 		assert.True(t, ok)
 	}
 }
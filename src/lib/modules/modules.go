@@ -8,6 +8,7 @@ import (
 	"github.com/cloe-lang/cloe/src/lib/modules/os"
 	"github.com/cloe-lang/cloe/src/lib/modules/random"
 	"github.com/cloe-lang/cloe/src/lib/modules/re"
+	"github.com/cloe-lang/cloe/src/lib/modules/test"
 )
 
 // Modules is a set of built-in modules
@@ -18,4 +19,5 @@ var Modules = map[string]map[string]core.Value{
 	"os":     os.Module,
 	"random": random.Module,
 	"re":     re.Module,
+	"test":   test.Module,
 }
@@ -0,0 +1,29 @@
+package test
+
+import "github.com/cloe-lang/cloe/src/lib/core"
+
+var assertEqual = core.NewEffectFunction(
+	core.NewSignature([]string{"actual", "expected"}, "", nil, ""),
+	func(vs ...core.Value) core.Value {
+		b, err := core.EvalBoolean(core.PApp(core.Equal, vs[0], vs[1]))
+
+		if err != nil {
+			return err
+		} else if b {
+			return core.Nil
+		}
+
+		ss := make([]core.StringType, 0, 2)
+
+		for _, v := range vs {
+			s, err := core.StrictDump(v)
+
+			if err != nil {
+				return err
+			}
+
+			ss = append(ss, s)
+		}
+
+		return assertionError("%s is not equal to %s", ss[0], ss[1])
+	})
@@ -0,0 +1,34 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertEqual(t *testing.T) {
+	for _, vs := range [][2]core.Value{
+		{core.NewNumber(42), core.NewNumber(42)},
+		{core.NewString("foo"), core.NewString("foo")},
+		{core.NewList(core.True, core.Nil), core.NewList(core.True, core.Nil)},
+	} {
+		assert.Equal(t, core.Nil, core.EvalImpure(core.PApp(assertEqual, vs[0], vs[1])))
+	}
+}
+
+func TestAssertEqualError(t *testing.T) {
+	for _, c := range []struct {
+		args []core.Value
+		name string
+	}{
+		{[]core.Value{core.NewNumber(42), core.NewNumber(2049)}, "AssertionError"},
+		{[]core.Value{core.NewString("foo"), core.NewNumber(42)}, "AssertionError"},
+		{[]core.Value{core.DummyError, core.NewNumber(42)}, "DummyError"},
+		{[]core.Value{core.NewNumber(42)}, "ArgumentError"},
+	} {
+		err, ok := core.EvalImpure(core.PApp(assertEqual, c.args...)).(*core.ErrorType)
+		assert.True(t, ok)
+		assert.Equal(t, c.name, err.Name())
+	}
+}
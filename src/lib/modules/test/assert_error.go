@@ -0,0 +1,29 @@
+package test
+
+import "github.com/cloe-lang/cloe/src/lib/core"
+
+var assertError = core.NewEffectFunction(
+	core.NewSignature([]string{"expr", "name"}, "", nil, ""),
+	func(vs ...core.Value) core.Value {
+		n, err := core.EvalString(vs[1])
+
+		if err != nil {
+			return err
+		}
+
+		v := core.EvalPure(core.PApp(core.Catch, vs[0]))
+
+		if v == core.Nil {
+			return assertionError("%s is not thrown", n)
+		}
+
+		m, err := core.EvalString(core.PApp(core.Index, v, core.NewString("name")))
+
+		if err != nil {
+			return err
+		} else if m != n {
+			return assertionError("%s is thrown instead of %s", m, n)
+		}
+
+		return core.Nil
+	})
@@ -0,0 +1,26 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertError(t *testing.T) {
+	assert.Equal(
+		t,
+		core.Nil,
+		core.EvalImpure(core.PApp(assertError, core.DummyError, core.NewString("DummyError"))))
+}
+
+func TestAssertErrorError(t *testing.T) {
+	for _, vs := range [][]core.Value{
+		{core.NewNumber(42), core.NewString("DummyError")},
+		{core.DummyError, core.NewString("TypeError")},
+		{core.DummyError, core.Nil},
+	} {
+		_, ok := core.EvalImpure(core.PApp(assertError, vs...)).(*core.ErrorType)
+		assert.True(t, ok)
+	}
+}
@@ -0,0 +1,13 @@
+package test
+
+import "github.com/cloe-lang/cloe/src/lib/core"
+
+var assertThrowsNothing = core.NewEffectFunction(
+	core.NewSignature([]string{"expr"}, "", nil, ""),
+	func(vs ...core.Value) core.Value {
+		if _, err := core.StrictDump(vs[0]); err != nil {
+			return err
+		}
+
+		return core.Nil
+	})
@@ -0,0 +1,28 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertThrowsNothing(t *testing.T) {
+	for _, v := range []core.Value{
+		core.NewNumber(42),
+		core.NewList(core.NewNumber(42), core.Nil),
+	} {
+		assert.Equal(t, core.Nil, core.EvalImpure(core.PApp(assertThrowsNothing, v)))
+	}
+}
+
+func TestAssertThrowsNothingError(t *testing.T) {
+	for _, v := range []core.Value{
+		core.DummyError,
+		core.NewList(core.NewNumber(42), core.DummyError),
+	} {
+		err, ok := core.EvalImpure(core.PApp(assertThrowsNothing, v)).(*core.ErrorType)
+		assert.True(t, ok)
+		assert.Equal(t, "DummyError", err.Name())
+	}
+}
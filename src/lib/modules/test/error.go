@@ -0,0 +1,7 @@
+package test
+
+import "github.com/cloe-lang/cloe/src/lib/core"
+
+func assertionError(m string, xs ...interface{}) core.Value {
+	return core.NewError("AssertionError", m, xs...)
+}
@@ -0,0 +1,10 @@
+package test
+
+import "github.com/cloe-lang/cloe/src/lib/core"
+
+// Module is a module in the language.
+var Module = map[string]core.Value{
+	"assertEqual":         assertEqual,
+	"assertError":         assertError,
+	"assertThrowsNothing": assertThrowsNothing,
+}
@@ -9,6 +9,7 @@ import (
 func (s *State) Char(r rune) Parser {
 	return func() (interface{}, error) {
 		if s.currentRune() != r {
+			s.expect("`" + string(r) + "`")
 			return nil, fmt.Errorf("invalid character, '%c'", s.currentRune())
 		}
 
@@ -32,7 +33,7 @@ func (s *State) String(str string) Parser {
 		ps = append(ps, s.Char(r))
 	}
 
-	return s.Stringify(s.And(ps...))
+	return s.Label("`"+str+"`", s.Stringify(s.And(ps...)))
 }
 
 // Chars creates a parser parsing a character in a given string.
@@ -54,7 +55,7 @@ func (s *State) NotChars(str string) Parser {
 	rs := stringToRuneSet(str)
 
 	return func() (interface{}, error) {
-		if _, ok := rs[s.currentRune()]; !ok {
+		if _, ok := rs[s.currentRune()]; !ok && !s.exhausted() {
 			defer s.increment()
 			return s.currentRune(), nil
 		}
@@ -169,6 +170,56 @@ func (s *State) And(ps ...Parser) Parser {
 	}
 }
 
+// Label creates a parser which describes what a given parser expects as a
+// name on failure at its starting position. An empty name hides what a given
+// parser expects.
+func (s *State) Label(name string, p Parser) Parser {
+	return func() (interface{}, error) {
+		old := *s.failure
+		i, l, c := s.sourcePosition, s.lineNumber, s.linePosition
+		x, err := p()
+
+		f := s.failure
+
+		if name == "" {
+			*f = old
+		} else if f.sourcePosition == i && f.expected != nil ||
+			err != nil && (f.sourcePosition < i || f.expected == nil) {
+			if old.sourcePosition == i && old.expected != nil {
+				*f = old
+				f.add(name)
+			} else {
+				*f = failure{l, c, i, []string{name}}
+			}
+		}
+
+		return x, err
+	}
+}
+
+// SkipUntil creates a parser which skips at least one character and then
+// characters until a given parser succeeds. It does not consume what the
+// given parser parses.
+func (s *State) SkipUntil(p Parser) Parser {
+	return func() (interface{}, error) {
+		f := *s.failure
+
+		for s.increment(); !s.exhausted(); s.increment() {
+			old := *s
+			_, err := p()
+			*s = old
+
+			if err == nil {
+				break
+			}
+		}
+
+		*s.failure = f
+
+		return nil, nil
+	}
+}
+
 // Lazy evaluates and runs a given parser constructor. This is useful to define
 // recursive parsers.
 func (s *State) Lazy(f func() Parser) Parser {
@@ -209,3 +209,71 @@ func TestVoid(t *testing.T) {
 func exhaustError(State) error {
 	return fmt.Errorf("Parsing error")
 }
+
+func TestNotCharFailWithExhaustedSource(t *testing.T) {
+	s := NewState("")
+	x, err := s.NotChar(' ')()
+	assert.Nil(t, x)
+	assert.NotNil(t, err)
+}
+
+func TestLabel(t *testing.T) {
+	s := NewState("ac")
+	_, err := s.And(s.String("a"), s.Or(s.Label("b", s.Chars("b")), s.String("d")))()
+
+	assert.NotNil(t, err)
+
+	f, ss := s.Failure()
+
+	assert.Equal(t, 1, f.SourcePosition())
+	assert.Equal(t, 2, f.LinePosition())
+	assert.Equal(t, []string{"b", "`d`"}, ss)
+}
+
+func TestLabelWithDeeperFailure(t *testing.T) {
+	s := NewState("abd")
+	_, err := s.Label("abc", s.String("abc"))()
+
+	assert.NotNil(t, err)
+
+	f, ss := s.Failure()
+
+	assert.Equal(t, 2, f.SourcePosition())
+	assert.Equal(t, []string{"`c`"}, ss)
+}
+
+func TestLabelWithEmptyName(t *testing.T) {
+	s := NewState("b")
+	_, err := s.Or(s.Label("", s.String("a")), s.String("c"))()
+
+	assert.NotNil(t, err)
+
+	_, ss := s.Failure()
+
+	assert.Equal(t, []string{"`c`"}, ss)
+}
+
+func TestSkipUntil(t *testing.T) {
+	s := NewState("abcabc")
+	_, err := s.SkipUntil(s.String("a"))()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, s.SourcePosition())
+
+	_, err = s.SkipUntil(s.String("a"))()
+
+	assert.Nil(t, err)
+	assert.True(t, s.Exhausted())
+
+	_, ss := s.Failure()
+	assert.Nil(t, ss)
+}
+
+func TestResetFailure(t *testing.T) {
+	s := NewState("a")
+	s.String("b")()
+	s.ResetFailure()
+
+	_, ss := s.Failure()
+	assert.Nil(t, ss)
+}
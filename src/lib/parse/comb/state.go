@@ -6,17 +6,30 @@ import "strings"
 type State struct {
 	source                                   []rune
 	lineNumber, linePosition, sourcePosition int
+	failure                                  *failure
+}
+
+// failure is the farthest failure of parsers which is shared by copies of a
+// state on backtracking.
+type failure struct {
+	lineNumber, linePosition, sourcePosition int
+	expected                                 []string
 }
 
 // NewState creates a parser state.
 func NewState(source string) *State {
-	return &State{source: []rune(source)}
+	return &State{source: []rune(source), failure: &failure{}}
 }
 
 func (s State) exhausted() bool {
 	return s.sourcePosition >= len(s.source)
 }
 
+// Exhausted returns true if a source is exhausted.
+func (s State) Exhausted() bool {
+	return s.exhausted()
+}
+
 func (s State) currentRune() rune {
 	if s.exhausted() {
 		return '\x00'
@@ -28,6 +41,9 @@ func (s State) currentRune() rune {
 func (s *State) increment() {
 	if s.currentRune() == '\n' {
 		s.lineNumber++
+		s.linePosition = 0
+	} else {
+		s.linePosition++
 	}
 
 	s.sourcePosition++
@@ -47,3 +63,42 @@ func (s State) LinePosition() int {
 func (s *State) Line() string {
 	return strings.Split(string(s.source), "\n")[s.lineNumber]
 }
+
+// SourcePosition returns a current position in a source.
+func (s State) SourcePosition() int {
+	return s.sourcePosition
+}
+
+// Failure returns a state at the farthest failure of parsers and descriptions
+// of what were expected there.
+func (s State) Failure() (State, []string) {
+	f := s.failure
+	return State{s.source, f.lineNumber, f.linePosition, f.sourcePosition, f}, f.expected
+}
+
+// ResetFailure forgets the farthest failure of parsers.
+func (s *State) ResetFailure() {
+	*s.failure = failure{}
+}
+
+// expect records something expected at a current position.
+func (s *State) expect(x string) {
+	f := s.failure
+
+	if s.sourcePosition > f.sourcePosition || f.expected == nil {
+		*f = failure{s.lineNumber, s.linePosition, s.sourcePosition, []string{x}}
+		return
+	} else if s.sourcePosition == f.sourcePosition {
+		f.add(x)
+	}
+}
+
+func (f *failure) add(x string) {
+	for _, y := range f.expected {
+		if x == y {
+			return
+		}
+	}
+
+	f.expected = append(f.expected, x)
+}
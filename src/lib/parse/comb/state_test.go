@@ -31,3 +31,16 @@ func TestStateLineNumber(t *testing.T) {
 func TestStateLinePosition(t *testing.T) {
 	assert.Equal(t, 1, NewState("").LinePosition())
 }
+
+func TestStateLinePositionAfterRunes(t *testing.T) {
+	s := NewState("ab\ncd")
+
+	_, err := s.String("a")()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.LinePosition())
+
+	_, err = s.String("b\nc")()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.LineNumber())
+	assert.Equal(t, 2, s.LinePosition())
+}
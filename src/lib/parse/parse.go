@@ -1,34 +1,51 @@
 package parse
 
 import (
+	"errors"
 	"fmt"
 	"path"
 	"strconv"
+	"strings"
 
 	"github.com/cloe-lang/cloe/src/lib/ast"
 	"github.com/cloe-lang/cloe/src/lib/consts"
 	"github.com/cloe-lang/cloe/src/lib/debug"
 	"github.com/cloe-lang/cloe/src/lib/parse/comb"
+	"github.com/cloe-lang/cloe/src/lib/types"
 )
 
 const (
-	defString       = "def"
-	commentChar     = ';'
-	importString    = "import"
-	invalidChars    = "\x00"
-	letString       = "let"
-	mutualRecString = "mr"
-	matchString     = "match"
-	spaceChars      = " \t\n\r"
-	specialChars    = "()[]{}\"\\$"
+	defString        = "def"
+	defMacroString   = "defmacro"
+	defTypeString    = "deftype"
+	commentChar      = ';'
+	exportString     = "export"
+	guardString      = "|"
+	importString     = "import"
+	invalidChars     = "\x00"
+	letString        = "let"
+	mutualRecString  = "mr"
+	orPatternString  = "or"
+	requiredString   = "!"
+	matchString      = "match"
+	spaceChars       = " \t\n\r"
+	specialChars     = "()[]{}\"\\$`"
+	threadString     = "->"
+	threadLastString = "->>"
 )
 
 var reserveds = map[string]bool{
-	defString:       true,
-	importString:    true,
-	letString:       true,
-	matchString:     true,
-	mutualRecString: true,
+	defString:        true,
+	defMacroString:   true,
+	defTypeString:    true,
+	exportString:     true,
+	guardString:      true,
+	importString:     true,
+	letString:        true,
+	matchString:      true,
+	mutualRecString:  true,
+	threadString:     true,
+	threadLastString: true,
 }
 
 // MainModule parses a main module file into an AST.
@@ -54,18 +71,47 @@ func SubModule(file, source string) ([]interface{}, error) {
 }
 
 func (s *state) mainModule() comb.Parser {
-	return s.Prefix(s.Maybe(s.shebang()), s.module(s.importModule(), s.let(), s.effect()))
+	return s.Prefix(s.Maybe(s.shebang()), s.module(
+		s.importModule(), s.export(), s.defMacro(), s.defType(), s.let(), s.effect()))
 }
 
 func (s *state) subModule() comb.Parser {
-	return s.module(s.importModule(), s.let())
+	return s.module(s.importModule(), s.export(), s.defMacro(), s.defType(), s.let())
 }
 
+// module creates a parser of top-level forms. It resynchronizes at the next
+// line beginning with a form on failure and reports all syntax errors.
 func (s *state) module(ps ...comb.Parser) comb.Parser {
-	return s.exhaust(s.Prefix(s.blank(), s.App(func(x interface{}) interface{} {
-		xs := x.([]interface{})
-		return append(xs[0].([]interface{}), xs[1].([]interface{})...)
-	}, s.And(s.Many(s.importModule()), s.Many(s.Or(ps...))))))
+	p := s.Or(ps...)
+	b := s.blank()
+	skip := s.SkipUntil(s.Or(s.String("\n("), s.String("\n..")))
+
+	return func() (interface{}, error) {
+		xs := []interface{}{}
+		es := debug.Errors(nil)
+
+		b()
+
+		for !s.Exhausted() {
+			i := s.SourcePosition()
+			s.ResetFailure()
+
+			if x, err := p(); err == nil {
+				xs = append(xs, x)
+				continue
+			}
+
+			skip()
+			es = append(es, s.syntaxError(i, s.SourcePosition()))
+			b()
+		}
+
+		if len(es) != 0 {
+			return nil, es
+		}
+
+		return xs, nil
+	}
 }
 
 func (s *state) importModule() comb.Parser {
@@ -73,7 +119,8 @@ func (s *state) importModule() comb.Parser {
 		s.list(
 			s.strippedString(importString),
 			s.Maybe(s.Or(s.identifier(), s.strippedString("."))),
-			s.stringLiteral()),
+			s.stringLiteral(),
+			s.Maybe(s.Or(s.importedNames(), s.importAlias()))),
 		func(x interface{}, i *debug.Info) (interface{}, error) {
 			xs := x.([]interface{})
 
@@ -85,13 +132,104 @@ func (s *state) importModule() comb.Parser {
 
 			q, ok := xs[1].(string)
 
-			if !ok {
+			if ast.IsDataFile(p) {
+				return dataImport(p, q, ok, xs[3], i)
+			} else if !ok {
 				q = path.Base(p)
 			} else if q == "." {
 				q = ""
 			}
 
-			return ast.NewImport(p, q, i), nil
+			if xs[3] == nil {
+				return ast.NewImport(p, q, nil, i), nil
+			} else if ok {
+				return nil, errors.New("selective imports cannot have prefixes")
+			}
+
+			ns, ok := xs[3].([]ast.ImportedName)
+
+			if !ok {
+				return nil, errors.New("only data files can be imported with aliases")
+			}
+			as := map[string]bool{}
+
+			for _, n := range ns {
+				if reserveds[n.Alias()] {
+					return nil, fmt.Errorf("%#v is a reserved identifier and must be imported with an alias", n.Alias())
+				} else if as[n.Alias()] {
+					return nil, fmt.Errorf("name %s is imported more than once", n.Alias())
+				}
+
+				as[n.Alias()] = true
+			}
+
+			return ast.NewImport(p, "", ns, i), nil
+		})
+}
+
+// dataImport creates an import of a data file bound to a prefix, an alias, or
+// a name of the file without its extension.
+func dataImport(p, q string, prefixed bool, x interface{}, i *debug.Info) (interface{}, error) {
+	if a, ok := x.(string); ok {
+		if prefixed {
+			return nil, errors.New("data files cannot have both prefixes and aliases")
+		}
+
+		q = a
+	} else if x != nil {
+		return nil, errors.New("members of data files cannot be imported selectively")
+	} else if !prefixed {
+		q = strings.TrimSuffix(path.Base(p), path.Ext(p))
+	}
+
+	if q == "." {
+		return nil, errors.New("data files cannot be imported into modules directly")
+	}
+
+	return ast.NewImport(p, q, nil, i), nil
+}
+
+// importedNames parses names of members imported selectively. Each name can
+// be followed by `. as` and its alias.
+func (s *state) importedNames() comb.Parser {
+	return s.App(
+		func(x interface{}) interface{} {
+			ns := []ast.ImportedName{}
+
+			for _, x := range x.([]interface{})[0].([]interface{}) {
+				xs := x.([]interface{})
+				n := xs[0].(string)
+
+				if a, ok := xs[1].(string); ok {
+					ns = append(ns, ast.NewImportedName(n, a))
+				} else {
+					ns = append(ns, ast.NewImportedName(n, n))
+				}
+			}
+
+			return ns
+		},
+		s.list(s.Many1(s.And(
+			s.strip(s.anyName("")),
+			s.Maybe(s.importAlias())))))
+}
+
+// importAlias parses an alias of an imported name or data file after `. as`.
+func (s *state) importAlias() comb.Parser {
+	return s.Prefix(s.And(s.strippedString("."), s.strippedString("as")), s.identifier())
+}
+
+func (s *state) export() comb.Parser {
+	return s.withInfo(
+		s.list(s.strippedString(exportString), s.Many1(s.identifier())),
+		func(x interface{}, i *debug.Info) (interface{}, error) {
+			ns := []string{}
+
+			for _, n := range x.([]interface{})[1].([]interface{}) {
+				ns = append(ns, n.(string))
+			}
+
+			return ast.NewExport(ns, i), nil
 		})
 }
 
@@ -104,10 +242,12 @@ func (s *state) strictLet() comb.Parser {
 }
 
 func (s *state) letVar() comb.Parser {
-	return s.App(func(x interface{}) interface{} {
-		xs := x.([]interface{})
-		return ast.NewLetVar(xs[1].(string), xs[2])
-	}, s.list(s.strippedString(letString), s.identifier(), s.expression()))
+	return s.withInfo(
+		s.list(s.strippedString(letString), s.identifier(), s.expression()),
+		func(x interface{}, i *debug.Info) (interface{}, error) {
+			xs := x.([]interface{})
+			return ast.NewLetVarWithInfo(xs[1].(string), xs[2], i), nil
+		})
 }
 
 func (s *state) letMatch() comb.Parser {
@@ -117,103 +257,265 @@ func (s *state) letMatch() comb.Parser {
 	}, s.list(s.strippedString(letString), s.Or(s.listLiteral(), s.dictLiteral()), s.expression()))
 }
 
+func (s *state) defMacro() comb.Parser {
+	return s.App(func(x interface{}) interface{} {
+		return ast.NewDefMacro(x.(ast.DefFunction))
+	}, s.function(defMacroString))
+}
+
+func (s *state) defType() comb.Parser {
+	return s.withInfo(
+		s.list(
+			s.strippedString(defTypeString),
+			s.identifier(),
+			s.Many1(s.list(s.identifier(), s.Many(s.identifier())))),
+		func(x interface{}, i *debug.Info) (interface{}, error) {
+			xs := x.([]interface{})
+			n := xs[1].(string)
+
+			if builtinTypes[n] {
+				return nil, fmt.Errorf("type %s is already defined", n)
+			}
+
+			cs := []ast.Constructor{}
+			ns := map[string]bool{}
+
+			for _, x := range xs[2].([]interface{}) {
+				ys := x.([]interface{})
+				c := ys[0].(string)
+
+				if ns[c] {
+					return nil, fmt.Errorf("constructor %s is duplicate", c)
+				}
+
+				ns[c] = true
+				fs := []string{}
+				ms := map[string]bool{}
+
+				for _, y := range ys[1].([]interface{}) {
+					f := y.(string)
+
+					if ms[f] {
+						return nil, fmt.Errorf("field %s of %s is duplicate", f, c)
+					}
+
+					ms[f] = true
+					fs = append(fs, f)
+				}
+
+				cs = append(cs, ast.NewConstructor(c, fs))
+			}
+
+			return ast.NewDefType(n, cs, i), nil
+		})
+}
+
+// builtinTypes are names of built-in types which user-defined types cannot
+// have.
+var builtinTypes = map[string]bool{
+	"boolean":    true,
+	"dictionary": true,
+	"function":   true,
+	"list":       true,
+	"nil":        true,
+	"number":     true,
+	"string":     true,
+}
+
 func (s *state) letFunction() comb.Parser {
+	return s.function(defString)
+}
+
+func (s *state) function(keyword string) comb.Parser {
 	return s.withInfo(
 		s.list(
-			s.strippedString(defString),
+			s.strippedString(keyword),
 			s.list(s.identifier(), s.signature()),
+			s.Maybe(s.strip(s.Prefix(s.String(":"), s.typ()))),
 			s.Many(s.let()),
 			s.expression()),
 		func(x interface{}, i *debug.Info) (interface{}, error) {
 			xs := x.([]interface{})
 			ys := xs[1].([]interface{})
-			return ast.NewDefFunction(ys[0].(string), ys[1].(ast.Signature), xs[2].([]interface{}), xs[3], i), nil
+			g := ys[1].(ast.Signature)
+			r, _ := xs[2].(types.Type)
+
+			return ast.NewDefFunction(
+				ys[0].(string),
+				g.WithTypes(g.Types(), r),
+				xs[3].([]interface{}),
+				xs[4],
+				i), nil
 		})
 }
 
+// parameter is a parameter name with an optional type annotation.
+type parameter struct {
+	name string
+	typ  types.Type
+}
+
+func (s *state) parameter() comb.Parser {
+	return s.App(func(x interface{}) interface{} {
+		xs := x.([]interface{})
+		t, _ := xs[1].(types.Type)
+		return parameter{xs[0].(string), t}
+	}, s.strip(s.And(s.name(":"), s.Maybe(s.Prefix(s.String(":"), s.typ())))))
+}
+
 func (s *state) optionalParameter() comb.Parser {
 	return s.App(func(x interface{}) interface{} {
 		xs := x.([]interface{})
-		return ast.NewOptionalParameter(xs[0].(string), xs[1])
-	}, s.strip(s.And(s.identifier(), s.expression())))
+		return [2]interface{}{xs[0], xs[1]}
+	}, s.strip(s.And(s.parameter(), s.expression())))
+}
+
+func (s *state) requiredParameter() comb.Parser {
+	return s.App(func(x interface{}) interface{} {
+		return [2]interface{}{x, nil}
+	}, s.strip(s.Prefix(s.String(requiredString), s.parameter())))
 }
 
-func (s *state) expandedArgument() comb.Parser {
-	return s.strip(s.expanded(s.identifier()))
+func (s *state) expandedParameter() comb.Parser {
+	return s.strip(s.expanded(s.parameter()))
 }
 
-func (s *state) positionalParameters() comb.Parser {
+func (s *state) signature() comb.Parser {
 	return s.App(func(x interface{}) interface{} {
 		xs := x.([]interface{})
-		ys := xs[0].([]interface{})
+		ts := map[string]types.Type{}
 
-		ss := make([]string, 0, len(ys))
+		name := func(x interface{}) string {
+			p, ok := x.(parameter)
 
-		for _, y := range ys {
-			ss = append(ss, y.(string))
+			if !ok {
+				return ""
+			} else if p.typ != nil {
+				ts[p.name] = p.typ
+			}
+
+			return p.name
 		}
 
-		s := ""
+		ps := []string{}
 
-		if xs[1] != nil {
-			s = xs[1].(string)
+		for _, x := range xs[0].([]interface{}) {
+			ps = append(ps, name(x))
 		}
 
-		return [2]interface{}{ss, s}
-	}, s.And(s.Many(s.identifier()), s.Maybe(s.expandedArgument())))
-}
+		pr := name(xs[1])
+		ks := []ast.OptionalParameter(nil)
+		kr := ""
 
-func (s *state) keywordParameters() comb.Parser {
-	return s.App(func(x interface{}) interface{} {
-		xs := x.([]interface{})
-		ys := xs[0].([]interface{})
+		if ys, ok := xs[2].([]interface{}); ok {
+			for _, y := range ys[0].([]interface{}) {
+				o := y.([2]interface{})
 
-		os := make([]ast.OptionalParameter, 0, len(ys))
+				if o[1] == nil {
+					ks = append(ks, ast.NewRequiredParameter(name(o[0])))
+				} else {
+					ks = append(ks, ast.NewOptionalParameter(name(o[0]), o[1]))
+				}
+			}
 
-		for _, y := range ys {
-			os = append(os, y.(ast.OptionalParameter))
+			kr = name(ys[1])
 		}
 
-		s := ""
+		if len(ts) == 0 {
+			ts = nil
+		}
 
-		if xs[1] != nil {
-			s = xs[1].(string)
+		return ast.NewSignature(ps, pr, ks, kr).WithTypes(ts, nil)
+	}, s.And(
+		s.Many(s.parameter()),
+		s.Maybe(s.expandedParameter()),
+		s.Maybe(s.Prefix(
+			s.strippedString("."),
+			s.And(
+				s.Many(s.Or(s.requiredParameter(), s.optionalParameter())),
+				s.Maybe(s.expandedParameter()))))))
+}
+
+// typ parses a type annotation of a union of type names. Type names of lists
+// and dictionaries are optionally followed by types of their elements in
+// brackets.
+func (s *state) typ() comb.Parser {
+	return s.Label("type", s.App(func(x interface{}) interface{} {
+		xs := x.([]interface{})
+		ts := []types.Type{xs[0].(types.Type)}
+
+		for _, x := range xs[1].([]interface{}) {
+			ts = append(ts, x.(types.Type))
 		}
 
-		return [2]interface{}{os, s}
-	}, s.And(s.Many(s.optionalParameter()), s.Maybe(s.expandedArgument())))
+		return types.Join(ts...)
+	}, s.And(s.primaryType(), s.Many(s.Prefix(s.String(guardString), s.primaryType())))))
 }
 
-func (s *state) signature() comb.Parser {
+func (s *state) primaryType() comb.Parser {
+	cs := string(commentChar) + invalidChars + spaceChars + specialChars + guardString + ":"
+	p := s.And(
+		s.Stringify(s.Many1(s.NotChars(cs))),
+		s.Maybe(s.Wrap(s.And(s.String("["), s.blank()), s.Many1(s.strip(s.Lazy(s.typ))), s.String("]"))))
+
+	return func() (interface{}, error) {
+		x, err := p()
+
+		if err != nil {
+			return nil, err
+		}
 
-	return s.App(func(x interface{}) interface{} {
 		xs := x.([]interface{})
+		ts, _ := xs[1].([]interface{})
+		return newType(xs[0].(string), ts)
+	}
+}
 
-		pas := xs[0].([2]interface{})
-		kas, ok := xs[1].([2]interface{})
+func newType(n string, xs []interface{}) (types.Type, error) {
+	ts := make([]types.Type, 0, len(xs))
 
-		if !ok {
-			kas = [2]interface{}{[]ast.OptionalParameter(nil), ""}
-		}
+	for _, x := range xs {
+		ts = append(ts, x.(types.Type))
+	}
+
+	if t, ok := map[string]types.Type{
+		"any":      types.Any,
+		"boolean":  types.Boolean,
+		"function": types.AnyFunction,
+		"nil":      types.Nil,
+		"number":   types.Number,
+		"string":   types.String,
+	}[n]; ok && len(ts) == 0 {
+		return t, nil
+	}
 
-		return ast.NewSignature(
-			pas[0].([]string), pas[1].(string),
-			kas[0].([]ast.OptionalParameter), kas[1].(string),
-		)
-	}, s.And(s.positionalParameters(), s.Maybe(s.Prefix(s.strippedString("."), s.keywordParameters()))))
+	switch {
+	case n == "list" && len(ts) == 0:
+		return types.NewList(types.Any), nil
+	case n == "list" && len(ts) == 1:
+		return types.NewList(ts[0]), nil
+	case n == "dictionary" && len(ts) == 0:
+		return types.NewDictionary(types.Any, types.Any), nil
+	case n == "dictionary" && len(ts) == 2:
+		return types.NewDictionary(ts[0], ts[1]), nil
+	}
+
+	return nil, fmt.Errorf("invalid type %s", n)
 }
 
 func (s *state) effect() comb.Parser {
-	return s.App(func(x interface{}) interface{} {
-		xs := x.([]interface{})
-		expanded := false
+	return s.withInfo(
+		s.And(s.Maybe(s.String("..")), s.expression()),
+		func(x interface{}, i *debug.Info) (interface{}, error) {
+			xs := x.([]interface{})
+			expanded := false
 
-		if xs[0] != nil {
-			expanded = true
-		}
+			if xs[0] != nil {
+				expanded = true
+			}
 
-		return ast.NewEffect(xs[1], expanded)
-	}, s.And(s.Maybe(s.String("..")), s.expression()))
+			return ast.NewEffect(xs[1], expanded, i), nil
+		})
 }
 
 func (s *state) expanded(p comb.Parser) comb.Parser {
@@ -221,14 +523,16 @@ func (s *state) expanded(p comb.Parser) comb.Parser {
 }
 
 func (s *state) expression() comb.Parser {
-	return s.Lazy(s.strictExpression)
+	return s.Label("expression", s.Lazy(s.strictExpression))
 }
 
 func (s *state) strictExpression() comb.Parser {
 	return s.strip(s.Or(
 		s.identifier(),
 		s.stringLiteral(),
+		s.interpolation(),
 		s.match(),
+		s.thread(),
 		s.app(),
 		s.listLiteral(),
 		s.dictLiteral(),
@@ -237,11 +541,11 @@ func (s *state) strictExpression() comb.Parser {
 }
 
 func (s *state) listLiteral() comb.Parser {
-	return s.appFunc(consts.Names.ListFunction, s.sequence("[", "]"))
+	return s.appFunc(consts.Names.ListFunction, s.sequence("[", s.expression(), "]"))
 }
 
 func (s *state) dictLiteral() comb.Parser {
-	return s.appFunc(consts.Names.DictionaryFunction, s.sequence("{", "}"))
+	return s.appFunc(consts.Names.DictionaryFunction, s.sequence("{", s.expression(), "}"))
 }
 
 func (s *state) anonymousFunction() comb.Parser {
@@ -259,23 +563,109 @@ func (s *state) match() comb.Parser {
 		cs := make([]ast.MatchCase, 0, len(ks))
 
 		for _, k := range ks {
-			xs := k.([]interface{})
-			cs = append(cs, ast.NewMatchCase(xs[0], xs[1]))
+			cs = append(cs, k.(ast.MatchCase))
 		}
 
 		return ast.NewMatch(xs[1], cs)
 	}, s.list(
 		s.strippedString(matchString),
 		s.expression(),
-		s.Many1(s.And(s.pattern(), s.expression()))))
+		s.Many1(s.matchCase())))
+}
+
+func (s *state) thread() comb.Parser {
+	return s.withInfo(
+		s.list(s.threadArrow(), s.expression(), s.Many1(s.threadStep())),
+		func(x interface{}, i *debug.Info) (interface{}, error) {
+			xs := x.([]interface{})
+			ys := xs[2].([]interface{})
+			as := make([]ast.App, 0, len(ys))
+
+			for _, y := range ys {
+				as = append(as, y.(ast.App))
+			}
+
+			return ast.NewThread(xs[0] == threadLastString, xs[1], as, i), nil
+		})
+}
+
+func (s *state) threadArrow() comb.Parser {
+	p := s.strip(s.anyName(""))
+
+	return func() (interface{}, error) {
+		x, err := p()
+
+		if err != nil {
+			return nil, err
+		} else if x != threadString && x != threadLastString {
+			return nil, fmt.Errorf("%#v is not a threading arrow", x)
+		}
+
+		return x, nil
+	}
+}
+
+// threadStep parses a step in a threading form. A step of a non-application
+// expression is regarded as an application of it to no argument.
+func (s *state) threadStep() comb.Parser {
+	return s.withInfo(
+		s.expression(),
+		func(x interface{}, i *debug.Info) (interface{}, error) {
+			if a, ok := x.(ast.App); ok {
+				return a, nil
+			}
+
+			return ast.NewApp(x, ast.NewArguments(nil, nil), i), nil
+		})
+}
+
+func (s *state) matchCase() comb.Parser {
+	return s.App(func(x interface{}) interface{} {
+		xs := x.([]interface{})
+		return ast.NewGuardedMatchCase(xs[0], xs[1], xs[2])
+	}, s.And(s.pattern(), s.Maybe(s.guard()), s.expression()))
+}
+
+func (s *state) guard() comb.Parser {
+	return s.Prefix(s.strippedString(guardString), s.expression())
 }
 
 func (s *state) pattern() comb.Parser {
-	return s.Or(
+	return s.Label("pattern", s.Lazy(s.strictPattern))
+}
+
+func (s *state) strictPattern() comb.Parser {
+	return s.strip(s.Or(
 		s.identifier(),
 		s.stringLiteral(),
-		s.listLiteral(),
-		s.dictLiteral())
+		s.appFunc(consts.Names.ListFunction, s.sequence("[", s.pattern(), "]")),
+		s.appFunc(consts.Names.DictionaryFunction, s.sequence("{", s.pattern(), "}")),
+		s.appPattern()))
+}
+
+// appPattern parses an or-pattern or a constructor pattern.
+func (s *state) appPattern() comb.Parser {
+	return s.withInfo(
+		s.list(s.identifier(), s.Many(s.pattern())),
+		func(x interface{}, i *debug.Info) (interface{}, error) {
+			xs := x.([]interface{})
+			f := xs[0]
+			ps := []ast.PositionalArgument{}
+
+			for _, p := range xs[1].([]interface{}) {
+				ps = append(ps, ast.NewPositionalArgument(p, false))
+			}
+
+			if f == orPatternString {
+				if len(ps) == 0 {
+					return nil, errors.New("or-pattern must have at least 1 pattern")
+				}
+
+				f = consts.Names.OrPattern
+			}
+
+			return ast.NewApp(f, ast.NewArguments(ps, nil), i), nil
+		})
 }
 
 func (s *state) mutuallyRecursiveDefFunctions() comb.Parser {
@@ -336,11 +726,11 @@ func (s *state) arguments() comb.Parser {
 
 		return ast.NewArguments(xs[0].([]ast.PositionalArgument), ks)
 	}, s.And(
-		s.positionalArguments(),
+		s.positionalArguments(s.expression()),
 		s.Maybe(s.Prefix(s.strippedString("."), s.keywordArguments()))))
 }
 
-func (s *state) positionalArguments() comb.Parser {
+func (s *state) positionalArguments(p comb.Parser) comb.Parser {
 	return s.App(func(x interface{}) interface{} {
 		xs := x.([]interface{})
 		ps := make([]ast.PositionalArgument, 0, len(xs))
@@ -350,17 +740,17 @@ func (s *state) positionalArguments() comb.Parser {
 		}
 
 		return ps
-	}, s.Many(s.positionalArgument()))
+	}, s.Many(s.positionalArgument(p)))
 }
 
-func (s *state) positionalArgument() comb.Parser {
+func (s *state) positionalArgument(p comb.Parser) comb.Parser {
 	unexpanded := s.App(func(x interface{}) interface{} {
 		return ast.NewPositionalArgument(x, false)
-	}, s.expression())
+	}, p)
 
 	expanded := s.App(func(x interface{}) interface{} {
 		return ast.NewPositionalArgument(x, true)
-	}, s.expanded(s.expression()))
+	}, s.expanded(p))
 
 	return s.Or(unexpanded, expanded)
 }
@@ -391,10 +781,14 @@ func (s *state) keywordArgument() comb.Parser {
 }
 
 func (s *state) identifier() comb.Parser {
-	cs := string(commentChar) + invalidChars + spaceChars + specialChars
-	p := s.strip(s.Stringify(s.And(s.NotChars(cs+"."), s.Stringify(s.Many(s.NotChars(cs))))))
+	return s.strip(s.name(""))
+}
 
-	return func() (interface{}, error) {
+// name parses a name which does not contain given characters.
+func (s *state) name(excluded string) comb.Parser {
+	p := s.anyName(excluded)
+
+	return s.Label("name", func() (interface{}, error) {
 		x, err := p()
 
 		if err != nil {
@@ -406,10 +800,20 @@ func (s *state) identifier() comb.Parser {
 		}
 
 		return x, nil
-	}
+	})
+}
+
+// anyName parses a name including reserved ones.
+func (s *state) anyName(excluded string) comb.Parser {
+	cs := string(commentChar) + invalidChars + spaceChars + specialChars + excluded
+	return s.Stringify(s.And(s.NotChars(cs+"."), s.Stringify(s.Many(s.NotChars(cs)))))
 }
 
 func (s *state) stringLiteral() comb.Parser {
+	return s.Label("string", s.Or(s.multiLineString(), s.quotedString(), s.rawString()))
+}
+
+func (s *state) quotedString() comb.Parser {
 	c := s.Char('"')
 
 	return s.Stringify(s.And(
@@ -424,14 +828,122 @@ func (s *state) stringLiteral() comb.Parser {
 		s.strip(c)))
 }
 
+// rawString parses a string literal quoted by backquotes in which no escape
+// sequence is processed.
+func (s *state) rawString() comb.Parser {
+	c := s.Char('`')
+
+	return s.App(func(x interface{}) interface{} {
+		return strconv.Quote(x.(string))
+	}, s.Wrap(c, s.Stringify(s.Many(s.NotChar('`'))), s.strip(c)))
+}
+
+// multiLineString parses a raw string literal quoted by triple double quotes.
+// Common indentation of its lines is stripped.
+func (s *state) multiLineString() comb.Parser {
+	q := s.String(`"""`)
+
+	return s.App(func(x interface{}) interface{} {
+		xs := x.([]interface{})
+		return strconv.Quote(dedent(xs[1].(string) + xs[2].(string)[3:]))
+	}, s.And(
+		q,
+		s.Stringify(s.Many(s.Or(
+			s.NotChar('"'),
+			s.And(s.Char('"'), s.NotChar('"')),
+			s.And(s.String(`""`), s.NotChar('"'))))),
+		s.strip(s.Stringify(s.And(q, s.Many(s.Char('"')))))))
+}
+
+// dedent removes a leading empty line and common indentation of lines in a
+// string. A last line of only spaces is the indentation of closing quotes.
+func dedent(s string) string {
+	ls := strings.Split(s, "\n")
+
+	if len(ls) > 1 && strings.TrimLeft(ls[0], " \t") == "" {
+		ls = ls[1:]
+	}
+
+	n := -1
+
+	for i, l := range ls {
+		if strings.TrimLeft(l, " \t") == "" && i != len(ls)-1 {
+			continue
+		}
+
+		if m := indentation(l); n < 0 || m < n {
+			n = m
+		}
+	}
+
+	for i, l := range ls {
+		if m := indentation(l); m < n {
+			ls[i] = l[m:]
+		} else {
+			ls[i] = l[n:]
+		}
+	}
+
+	return strings.Join(ls, "\n")
+}
+
+func indentation(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " \t"))
+}
+
+// interpolation parses an interpolated string literal in which expressions
+// are embedded in braces. Braces in strings are escaped by backslashes.
+func (s *state) interpolation() comb.Parser {
+	str := s.App(func(x interface{}) interface{} {
+		return "\"" + x.(string) + "\""
+	}, s.Stringify(s.Many(s.Or(
+		s.NotChars("\"\\{}"),
+		s.String("\\\""),
+		s.String("\\\\"),
+		s.String("\\n"),
+		s.String("\\t"),
+		s.Prefix(s.Char('\\'), s.Chars("{}")),
+	))))
+
+	return s.Label("string", s.withInfo(
+		s.And(
+			s.String("$\""),
+			str,
+			s.Many(s.And(
+				s.Wrap(
+					s.strippedString("{"),
+					s.withInfo(s.expression(), func(x interface{}, i *debug.Info) (interface{}, error) {
+						return []interface{}{x, i}, nil
+					}),
+					s.String("}")),
+				str)),
+			s.strip(s.Char('"'))),
+		func(x interface{}, i *debug.Info) (interface{}, error) {
+			xs := x.([]interface{})
+			ss := []string{xs[1].(string)}
+			es := []interface{}{}
+			is := []*debug.Info{}
+
+			for _, y := range xs[2].([]interface{}) {
+				ys := y.([]interface{})
+				zs := ys[0].([]interface{})
+				es = append(es, zs[0])
+				is = append(is, zs[1].(*debug.Info))
+				ss = append(ss, ys[1].(string))
+			}
+
+			return ast.NewInterpolation(ss, es, is, i), nil
+		}))
+}
+
 func (s *state) list(ps ...comb.Parser) comb.Parser {
 	return s.stringWrap("(", s.And(ps...), ")")
 }
 
-func (s *state) sequence(l, r string) comb.Parser {
+func (s *state) sequence(l string, p comb.Parser, r string) comb.Parser {
 	return s.App(func(x interface{}) interface{} {
 		return ast.NewArguments(x.([]ast.PositionalArgument), nil)
-	}, s.stringWrap(l, s.positionalArguments(), r))
+	}, s.stringWrap(l, s.positionalArguments(p), r))
 }
 
 func (s *state) stringWrap(l string, p comb.Parser, r string) comb.Parser {
@@ -452,7 +964,7 @@ func (s *state) strip(p comb.Parser) comb.Parser {
 }
 
 func (s *state) blank() comb.Parser {
-	return s.Void(s.Many(s.Or(s.Chars(spaceChars), s.comment())))
+	return s.Label("", s.Void(s.Many(s.Or(s.Chars(spaceChars), s.comment()))))
 }
 
 func (s *state) comment() comb.Parser {
@@ -466,8 +978,11 @@ func (s *state) strippedString(str string) comb.Parser {
 }
 
 func (s *state) exhaust(p comb.Parser) comb.Parser {
-	return s.Exhaust(p, func(s comb.State) error {
-		return fmt.Errorf("SyntaxError:%d:%d:\t%s", s.LineNumber(), s.LinePosition(), s.Line())
+	return s.Exhaust(p, func(c comb.State) error {
+		return debug.NewError(
+			debug.NewInfo(s.file, c.LineNumber(), c.LinePosition(), c.Line()),
+			"SyntaxError",
+			"invalid syntax")
 	})
 }
 
@@ -1,8 +1,12 @@
 package parse
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
 
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/consts"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -60,6 +64,13 @@ func TestImportModule(t *testing.T) {
 		`(import "foo/bar")`,
 		`(import bar "foo")`,
 		`(import . "foo")`,
+		`(import "foo" (bar))`,
+		`(import "foo" (bar baz))`,
+		`(import "foo" (bar . as baz))`,
+		`(import "foo" (match . as m find))`,
+		`(import "./foo.json")`,
+		`(import "./foo.txt" . as bar)`,
+		`(import bar "./foo.txt")`,
 	} {
 		s := newStateWithoutFile(str)
 		_, err := s.exhaust(s.importModule())()
@@ -67,14 +78,84 @@ func TestImportModule(t *testing.T) {
 	}
 }
 
+func TestImportModuleWithNames(t *testing.T) {
+	s := newStateWithoutFile(`(import "re" (match . as m find))`)
+	x, err := s.exhaust(s.importModule())()
+	assert.Nil(t, err)
+
+	i := x.(ast.Import)
+	assert.Equal(t, "(import re (match . as m find))", i.String())
+
+	for n, l := range map[string]string{"match": "m", "find": "find"} {
+		m, ok := i.LocalName(n)
+		assert.True(t, ok)
+		assert.Equal(t, l, m)
+	}
+
+	_, ok := i.LocalName("replace")
+	assert.False(t, ok)
+}
+
+func TestImportDataFile(t *testing.T) {
+	for str, n := range map[string]string{
+		`(import "./foo.json")`:          "foo",
+		`(import "foo/bar.baz.txt")`:     "bar.baz",
+		`(import "./foo.txt" . as bar)`:  "bar",
+		`(import bar "./foo.json")`:      "bar",
+		`(import "../foo.json" . as x?)`: "x?",
+	} {
+		s := newStateWithoutFile(str)
+		x, err := s.exhaust(s.importModule())()
+		assert.Nil(t, err)
+
+		i := x.(ast.Import)
+		assert.True(t, i.Data())
+
+		m, ok := i.LocalName(consts.Names.DataMember)
+		assert.True(t, ok)
+		assert.Equal(t, n, m)
+	}
+}
+
 func TestImportModuleFail(t *testing.T) {
-	for _, str := range []string{"(import)", "(import foo)", `(import "\a\b\c\d")`} {
+	for _, str := range []string{
+		"(import)",
+		"(import foo)",
+		`(import "\a\b\c\d")`,
+		`(import "foo" ())`,
+		`(import "foo" ("bar"))`,
+		`(import "foo" (bar . as))`,
+		`(import "foo" (bar baz . as bar))`,
+		`(import "foo" (match))`,
+		`(import foo "foo" (bar))`,
+		`(import "foo" . as bar)`,
+		`(import "foo.json" (bar))`,
+		`(import foo "foo.json" . as bar)`,
+		`(import . "foo.json")`,
+	} {
 		s := newStateWithoutFile(str)
 		_, err := s.exhaust(s.importModule())()
 		assert.NotNil(t, err)
 	}
 }
 
+func TestExport(t *testing.T) {
+	for _, str := range []string{"(export foo)", "(export foo bar? +)"} {
+		s := newStateWithoutFile(str)
+		x, err := s.exhaust(s.export())()
+		assert.Nil(t, err)
+		assert.Equal(t, str, x.(ast.Export).String())
+	}
+}
+
+func TestExportFail(t *testing.T) {
+	for _, str := range []string{"(export)", `(export "foo")`, "(export (foo))", "(export let)"} {
+		s := newStateWithoutFile(str)
+		_, err := s.exhaust(s.export())()
+		assert.NotNil(t, err)
+	}
+}
+
 func TestLetVar(t *testing.T) {
 	for _, str := range []string{"(let foo 123)", "(let foo (f x y))"} {
 		s := newStateWithoutFile(str)
@@ -99,6 +180,7 @@ func TestDefFunction(t *testing.T) {
 		"(def (foo) 123)",
 		"(def (foo x) (f x y))",
 		"(def (foo x y ..args . c 123 d 456 ..kwargs) 123)",
+		"(def (foo x . !timeout retries 3) 123)",
 	} {
 		s := newStateWithoutFile(str)
 		_, err := s.exhaust(s.letFunction())()
@@ -106,6 +188,48 @@ func TestDefFunction(t *testing.T) {
 	}
 }
 
+func TestDefMacro(t *testing.T) {
+	for _, str := range []string{
+		"(defmacro (foo) \"123\")",
+		"(defmacro (when c ..body) [\"if\" c [\"seq\" ..body] \"nil\"])",
+		"(defmacro (foo x) (let y [x x]) y)",
+	} {
+		s := newStateWithoutFile(str)
+		x, err := s.exhaust(s.defMacro())()
+		assert.Nil(t, err)
+		_, ok := x.(ast.DefMacro)
+		assert.True(t, ok)
+	}
+}
+
+func TestDefType(t *testing.T) {
+	for _, str := range []string{
+		"(deftype point (point x y))",
+		"(deftype shape (circle radius) (rectangle width height))",
+		"(deftype option (none) (some value))",
+	} {
+		s := newStateWithoutFile(str)
+		x, err := s.exhaust(s.defType())()
+		assert.Nil(t, err)
+		assert.Equal(t, str, x.(ast.DefType).String())
+	}
+}
+
+func TestDefTypeFail(t *testing.T) {
+	for _, str := range []string{
+		"(deftype point)",
+		"(deftype point point)",
+		"(deftype list (cons x xs))",
+		"(deftype shape (circle r) (circle r))",
+		"(deftype point (point x x))",
+		"(deftype point (point ..xs))",
+	} {
+		s := newStateWithoutFile(str)
+		_, err := s.exhaust(s.defType())()
+		assert.NotNil(t, err)
+	}
+}
+
 func TestMutuallyRecursiveDefFunctions(t *testing.T) {
 	for _, str := range []string{
 		`(mr
@@ -126,6 +250,96 @@ func TestSignature(t *testing.T) {
 	}
 }
 
+func TestSignatureWithRequiredKeywordParameters(t *testing.T) {
+	for _, c := range []struct {
+		source   string
+		required []bool
+	}{
+		{". !x", []bool{true}},
+		{". !x !y", []bool{true, true}},
+		{". x 42 !y", []bool{false, true}},
+		{". !x y 42 ..kwargs", []bool{true, false}},
+	} {
+		s := newStateWithoutFile(c.source)
+		x, err := s.exhaust(s.signature())()
+		assert.Nil(t, err)
+
+		bs := []bool{}
+
+		for _, k := range x.(ast.Signature).Keywords() {
+			bs = append(bs, k.Required())
+		}
+
+		assert.Equal(t, c.required, bs)
+	}
+}
+
+func TestSignatureWithTypes(t *testing.T) {
+	for _, c := range []struct {
+		source string
+		types  map[string]string
+	}{
+		{"x:number", map[string]string{"x": "number"}},
+		{"x:number y", map[string]string{"x": "number"}},
+		{"x:list[string]", map[string]string{"x": "list[string]"}},
+		{"x:list", map[string]string{"x": "list"}},
+		{"x:dictionary[string list[number]]", map[string]string{"x": "dictionary[string list[number]]"}},
+		{"x:dictionary[ string  number ]", map[string]string{"x": "dictionary[string number]"}},
+		{"x:number|nil", map[string]string{"x": "number|nil"}},
+		{"x:list[number|string]|nil", map[string]string{"x": "list[number|string]|nil"}},
+		{"x:function ..xs:list[boolean]", map[string]string{"x": "function", "xs": "list[boolean]"}},
+		{". y:string \"foo\" ..ys:dictionary", map[string]string{"y": "string", "ys": "dictionary"}},
+		{". !y:number z 42", map[string]string{"y": "number"}},
+		{"x:any", map[string]string{"x": "any"}},
+	} {
+		s := newStateWithoutFile(c.source)
+		x, err := s.exhaust(s.signature())()
+		assert.Nil(t, err)
+
+		ts := map[string]string{}
+
+		for n, t := range x.(ast.Signature).Types() {
+			ts[n] = t.String()
+		}
+
+		assert.Equal(t, c.types, ts)
+	}
+}
+
+func TestSignatureWithTypesFail(t *testing.T) {
+	for _, str := range []string{
+		"x:",
+		"x:foo",
+		"x:number[string]",
+		"x:list[number string]",
+		"x:dictionary[string]",
+		"x:number|",
+		"x: number",
+	} {
+		s := newStateWithoutFile(str)
+		_, err := s.exhaust(s.signature())()
+		assert.NotNil(t, err)
+	}
+}
+
+func TestDefFunctionWithResultType(t *testing.T) {
+	for _, c := range []struct{ source, result string }{
+		{"(def (f x:number) :number x)", "number"},
+		{"(def (f x) :list[number]|nil (let y x) y)", "list[number]|nil"},
+	} {
+		s := newStateWithoutFile(c.source)
+		x, err := s.exhaust(s.letFunction())()
+		assert.Nil(t, err)
+		assert.Equal(t, c.result, x.(ast.DefFunction).Signature().Result().String())
+	}
+
+	s := newStateWithoutFile("(def (f) :foo)")
+	x, err := s.exhaust(s.letFunction())()
+	assert.Nil(t, err)
+	assert.Nil(t, x.(ast.DefFunction).Signature().Result())
+	assert.Equal(t, ":foo", x.(ast.DefFunction).Body())
+}
+
 func TestEffect(t *testing.T) {
 	for _, str := range []string{"effect", "..effects", "(foo bar)", "..(foo bar)"} {
 		s := newStateWithoutFile(str)
@@ -143,6 +357,12 @@ func TestStringLiteral(t *testing.T) {
 		`"\\"`,
 		`"\n"`,
 		`"\t"`,
+		"``",
+		"`\\d+(\\.\\d+)?`",
+		"`\"quoted\"\n`",
+		`""""""`,
+		`"""foo"""`,
+		"\"\"\"\n  <p class=\"foo\">\"\"bar\"\"</p>\n  \"\"\"",
 	} {
 		s := newStateWithoutFile(str)
 		result, err := s.exhaust(s.stringLiteral())()
@@ -154,6 +374,154 @@ func TestStringLiteral(t *testing.T) {
 	}
 }
 
+func TestInterpolation(t *testing.T) {
+	for _, c := range []struct {
+		source      string
+		strings     []string
+		expressions int
+	}{
+		{`$""`, []string{`""`}, 0},
+		{`$"foo"`, []string{`"foo"`}, 0},
+		{`$"{x}"`, []string{`""`, `""`}, 1},
+		{`$"Hello, {name}! You have {n} items"`, []string{`"Hello, "`, `"! You have "`, `" items"`}, 2},
+		{`$"{ (f x) }"`, []string{`""`, `""`}, 1},
+		{`$"a{$"b{c}"}d"`, []string{`"a"`, `"d"`}, 1},
+		{`$"\{x\} \"\n"`, []string{`"{x} \"\n"`}, 0},
+		{`$"{"}"}"`, []string{`""`, `""`}, 1},
+	} {
+		s := newStateWithoutFile(c.source)
+		x, err := s.exhaust(s.expression())()
+
+		assert.Nil(t, err)
+
+		i, ok := x.(ast.Interpolation)
+		assert.True(t, ok)
+		assert.Equal(t, c.strings, i.Strings())
+		assert.Equal(t, c.expressions, len(i.Expressions()))
+	}
+}
+
+func TestInterpolationWithExpressionInfos(t *testing.T) {
+	s := newStateWithoutFile(`$"a{x}b{ (f y) }"`)
+	x, err := s.exhaust(s.expression())()
+
+	assert.Nil(t, err)
+
+	is := x.(ast.Interpolation).ExpressionInfos()
+
+	assert.Equal(t, 2, len(is))
+	assert.Equal(t, 5, is[0].LinePosition())
+	assert.Equal(t, 10, is[1].LinePosition())
+}
+
+func TestInterpolationFail(t *testing.T) {
+	for _, str := range []string{`$"`, `$"{x"`, `$"{}"`, `$"}"`, `$ "x"`} {
+		s := newStateWithoutFile(str)
+		_, err := s.exhaust(s.expression())()
+
+		assert.NotNil(t, err)
+	}
+}
+
+func TestThread(t *testing.T) {
+	for _, c := range []struct {
+		source string
+		last   bool
+		steps  []string
+	}{
+		{"(-> x f)", false, []string{"(f )"}},
+		{"(->> x f)", true, []string{"(f )"}},
+		{"(-> x (f y) g)", false, []string{"(f y)", "(g )"}},
+		{"(->> xs (sort . less g) (map f) (filter p))", true, []string{"(sort . less g)", "(map f)", "(filter p)"}},
+	} {
+		s := newStateWithoutFile(c.source)
+		x, err := s.exhaust(s.expression())()
+
+		assert.Nil(t, err)
+
+		r, ok := x.(ast.Thread)
+		assert.True(t, ok)
+		assert.Equal(t, c.last, r.Last())
+		assert.Equal(t, len(c.steps), len(r.Steps()))
+
+		for k, a := range r.Steps() {
+			assert.Equal(t, c.steps[k], a.String())
+		}
+	}
+}
+
+func TestThreadDebugInfo(t *testing.T) {
+	s := newStateWithoutFile("(->> xs\n  (map f)\n  g)")
+	x, err := s.exhaust(s.expression())()
+
+	assert.Nil(t, err)
+
+	for k, a := range x.(ast.Thread).Steps() {
+		assert.Equal(t, k+2, a.DebugInfo().LineNumber())
+		assert.Equal(t, 3, a.DebugInfo().LinePosition())
+	}
+}
+
+func TestThreadFail(t *testing.T) {
+	for _, str := range []string{"(-> x)", "(->>)", "(-> x . y z)", "(f -> x)"} {
+		s := newStateWithoutFile(str)
+		_, err := s.exhaust(s.expression())()
+
+		assert.NotNil(t, err)
+	}
+}
+
+func TestStringLiteralValue(t *testing.T) {
+	for _, c := range []struct {
+		source, value string
+	}{
+		{`"foo\n"`, "foo\n"},
+		{"`foo\\n`", "foo\\n"},
+		{"`(\"\n)`", "(\"\n)"},
+		{`"""foo"""`, "foo"},
+		{`"""say "hi""""`, `say "hi"`},
+		{"\"\"\"\n  foo\n    bar\n  \"\"\"", "foo\n  bar\n"},
+		{"\"\"\"\n  foo\n    bar\"\"\"", "foo\n  bar"},
+		{"\"\"\"\n    foo\n\n    bar\n  \"\"\"", "  foo\n\n  bar\n"},
+		{"\"\"\"\\n\\t\"\"\"", "\\n\\t"},
+	} {
+		s := newStateWithoutFile(c.source)
+		x, err := s.exhaust(s.stringLiteral())()
+
+		assert.Nil(t, err)
+
+		v, err := strconv.Unquote(x.(string))
+
+		assert.Nil(t, err)
+		assert.Equal(t, c.value, v)
+	}
+}
+
+func TestStringLiteralFail(t *testing.T) {
+	for _, str := range []string{"`", "`foo", `"""`, `"""foo""`, `""""`} {
+		s := newStateWithoutFile(str)
+		_, err := s.exhaust(s.stringLiteral())()
+
+		assert.NotNil(t, err)
+	}
+}
+
+func TestDedent(t *testing.T) {
+	for _, c := range []struct {
+		source, result string
+	}{
+		{"", ""},
+		{"foo", "foo"},
+		{"\n", ""},
+		{"\n  foo\n  ", "foo\n"},
+		{"\n\tfoo\n\t\tbar", "foo\n\tbar"},
+		{"\n  foo\n \n  bar", "foo\n\nbar"},
+		{"\n    foo\n  ", "  foo\n"},
+	} {
+		assert.Equal(t, c.result, dedent(c.source))
+	}
+}
+
 func TestStrip(t *testing.T) {
 	s := newStateWithoutFile("ident  \t ")
 	result, err := s.exhaust(s.strip(s.identifier()))()
@@ -215,6 +583,13 @@ func TestMatchExpression(t *testing.T) {
 		"(match 123 123 true)",
 		"(match (foo bar) [123 ..elems] (process elems) xs (print xs))",
 		"(match (foo bar) [\"foo\" 123 ..rest] (process rest) xs (print xs))",
+		"(match x y | (> y 0) y _ 0)",
+		"(match x [y ..ys] | (> y 0) y [_ ..ys] | (f ys) ys _ 0)",
+		"(match x (or 1 2) true _ false)",
+		"(match x [(or \"GET\" \"HEAD\") path] | (= path \"/\") true _ false)",
+		"(match x {\"key\" (or 1 (or 2 3)) ..rest} true)",
+		"(match x (circle r) r (rectangle w h) (* w h))",
+		"(match x (orange) 1 (none) 2)",
 	} {
 		s := newStateWithoutFile(str)
 		result, err := s.exhaust(s.match())()
@@ -226,6 +601,34 @@ func TestMatchExpression(t *testing.T) {
 	}
 }
 
+func TestMatchExpressionWithGuard(t *testing.T) {
+	s := newStateWithoutFile("(match x y | (> y 0) y _ 0)")
+	x, err := s.exhaust(s.match())()
+
+	assert.Nil(t, err)
+
+	cs := x.(ast.Match).Cases()
+
+	assert.Equal(t, "(> y 0)", fmt.Sprint(cs[0].Guard()))
+	assert.Nil(t, cs[1].Guard())
+}
+
+func TestMatchExpressionFail(t *testing.T) {
+	for _, str := range []string{
+		"(match x)",
+		"(match x y |)",
+		"(match x y | (> y 0))",
+		"(match x (or) true)",
+		"(match x ((f) y) true)",
+		"(match x [(f ..y)] true)",
+	} {
+		s := newStateWithoutFile(str)
+		_, err := s.exhaust(s.match())()
+
+		assert.NotNil(t, err)
+	}
+}
+
 func TestApp(t *testing.T) {
 	for _, str := range []string{
 		"(f)", "(f x)", "(f x y)", "(f ..x)", "(f . x 123)", "(f . x 123 y 456)",
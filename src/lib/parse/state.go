@@ -7,13 +7,43 @@ import (
 
 type state struct {
 	*comb.State
-	file string
+	file   string
+	source []rune
 }
 
 func newState(file, source string) *state {
-	return &state{comb.NewState(source), file}
+	return &state{comb.NewState(source), file, []rune(source)}
 }
 
 func (s state) debugInfo() *debug.Info {
 	return debug.NewInfo(s.file, s.LineNumber(), s.LinePosition(), s.Line())
 }
+
+// debugInfoAt creates debug information at a position in source code.
+func (s state) debugInfoAt(i int) *debug.Info {
+	l, c := s.lineColumn(i)
+	b, e := i-c+1, i
+
+	for e < len(s.source) && s.source[e] != '\n' {
+		e++
+	}
+
+	return debug.NewInfo(s.file, l, c, string(s.source[b:e]))
+}
+
+// lineColumn converts a position in source code into a line number and a
+// position in the line.
+func (s state) lineColumn(i int) (int, int) {
+	l, c := 1, 1
+
+	for _, r := range s.source[:i] {
+		if r == '\n' {
+			l++
+			c = 1
+		} else {
+			c++
+		}
+	}
+
+	return l, c
+}
@@ -0,0 +1,160 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+)
+
+var closingBrackets = map[rune]rune{'(': ')', '[': ']', '{': '}'}
+
+// syntaxError creates an error of a top-level form in source code between 2
+// positions. Unbalanced brackets and string literals are reported first, and
+// what were expected at the farthest failure of parsers otherwise.
+func (s *state) syntaxError(i, j int) error {
+	if err := s.bracketError(i, j); err != nil {
+		return err
+	}
+
+	f, ss := s.Failure()
+
+	if ss == nil {
+		return s.newSyntaxError(i, "invalid syntax")
+	}
+
+	return debug.NewError(
+		debug.NewInfo(s.file, f.LineNumber(), f.LinePosition(), f.Line()),
+		"SyntaxError",
+		"expected %s",
+		alternatives(ss))
+}
+
+func (s *state) bracketError(i, j int) error {
+	type bracket struct {
+		position int
+		name     string
+	}
+
+	bs := []bracket{}
+	rs := s.source
+
+	for k := i; k < j; k++ {
+		switch r := rs[k]; r {
+		case commentChar:
+			for k < j && rs[k] != '\n' {
+				k++
+			}
+		case '"', '`':
+			l := k
+			q := s.quote(k)
+
+			if q == `"` {
+				for k++; k < j && rs[k] != '"'; k++ {
+					if rs[k] == '\\' {
+						k++
+					}
+				}
+			} else {
+				for k += len(q); k < j && s.quote(k) != q; k++ {
+				}
+
+				k += len(q) - 1
+			}
+
+			if k >= j {
+				return s.newSyntaxError(
+					s.end(i, j),
+					"expected %s to close a string literal opened at %s",
+					code(q), s.position(l))
+			}
+		case '(', '[', '{':
+			bs = append(bs, bracket{k, s.openingName(k)})
+		case ')', ']', '}':
+			if len(bs) == 0 {
+				return s.newSyntaxError(k, "unexpected `%c`", r)
+			}
+
+			b := bs[len(bs)-1]
+			bs = bs[:len(bs)-1]
+
+			if c := closingBrackets[rs[b.position]]; c != r {
+				return s.newSyntaxError(
+					k,
+					"expected `%c` to close `%s` opened at %s but found `%c`",
+					c, b.name, s.position(b.position), r)
+			}
+		}
+	}
+
+	if len(bs) != 0 {
+		b := bs[len(bs)-1]
+
+		return s.newSyntaxError(
+			s.end(i, j),
+			"expected `%c` to close `%s` opened at %s",
+			closingBrackets[rs[b.position]], b.name, s.position(b.position))
+	}
+
+	return nil
+}
+
+// quote returns a quote of a string literal beginning at a position.
+func (s *state) quote(i int) string {
+	if s.source[i] == '`' {
+		return "`"
+	} else if i+2 < len(s.source) && string(s.source[i:i+3]) == `"""` {
+		return `"""`
+	}
+
+	return `"`
+}
+
+// code quotes a piece of source code by backquotes.
+func code(s string) string {
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+
+	return "`" + s + "`"
+}
+
+func (s *state) newSyntaxError(i int, m string, xs ...interface{}) error {
+	return debug.NewError(s.debugInfoAt(i), "SyntaxError", m, xs...)
+}
+
+// openingName returns an opening bracket with a name following it.
+func (s *state) openingName(i int) string {
+	cs := string(commentChar) + invalidChars + spaceChars + specialChars
+	j := i + 1
+
+	for j < len(s.source) && !strings.ContainsRune(cs, s.source[j]) {
+		j++
+	}
+
+	return string(s.source[i:j])
+}
+
+// end returns a position next to the last non-space character between 2
+// positions.
+func (s *state) end(i, j int) int {
+	for j > i && strings.ContainsRune(spaceChars, s.source[j-1]) {
+		j--
+	}
+
+	return j
+}
+
+func (s *state) position(i int) string {
+	l, c := s.lineColumn(i)
+	return fmt.Sprintf("%d:%d", l, c)
+}
+
+// alternatives joins descriptions of expected tokens.
+func alternatives(ss []string) string {
+	if len(ss) == 1 {
+		return ss[0]
+	}
+
+	return strings.Join(ss[:len(ss)-1], ", ") + " or " + ss[len(ss)-1]
+}
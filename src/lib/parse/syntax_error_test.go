@@ -0,0 +1,83 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMainModuleWithSyntaxErrors(t *testing.T) {
+	_, err := MainModule("main.cloe", `(def (f x)
+  (+ x 1)
+
+(print (f 1))
+(print 2))
+(let x)
+(print "foo)
+`)
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 4, len(es))
+
+	for i, c := range []struct {
+		line, column int
+		message      string
+	}{
+		{2, 10, "expected `)` to close `(def` opened at 1:1"},
+		{5, 10, "unexpected `)`"},
+		{6, 7, "expected expression"},
+		{7, 13, "expected `\"` to close a string literal opened at 7:8"},
+	} {
+		e := es[i].(*debug.Error)
+
+		assert.Equal(t, "SyntaxError", e.Name())
+		assert.Equal(t, c.message, e.Message())
+		assert.Equal(t, c.line, e.Info().LineNumber())
+		assert.Equal(t, c.column, e.Info().LinePosition())
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	for _, c := range []struct {
+		source, message string
+	}{
+		{"(print 123", "expected `)` to close `(print` opened at 1:1"},
+		{"(print [1 2)", "expected `]` to close `[1` opened at 1:8 but found `)`"},
+		{"(print {1 2}}", "expected `)` to close `(print` opened at 1:1 but found `}`"},
+		{"(print 1))", "unexpected `)`"},
+		{`(print "("`, "expected `)` to close `(print` opened at 1:1"},
+		{"(print `(`", "expected `)` to close `(print` opened at 1:1"},
+		{"(print `)", "expected `` ` `` to close a string literal opened at 1:8"},
+		{"(print \"\"\"foo)\n", "expected `\"\"\"` to close a string literal opened at 1:8"},
+		{"(print ; )\n", "expected `)` to close `(print` opened at 1:1"},
+		{"(def (def) 1)", "expected name"},
+		{"(def (f x) x y)", "expected `)`"},
+		{"(import)", "expected name, `.` or string"},
+	} {
+		_, err := MainModule("main.cloe", c.source)
+
+		es, ok := err.(debug.Errors)
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(es))
+
+		if len(es) > 0 {
+			assert.Equal(t, c.message, es[0].(*debug.Error).Message())
+		}
+	}
+}
+
+func TestSubModuleWithSyntaxErrors(t *testing.T) {
+	_, err := SubModule("module.cloe", "(print 123)\n(let x 1)\n(let y)")
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(es))
+}
+
+func TestAlternatives(t *testing.T) {
+	assert.Equal(t, "a", alternatives([]string{"a"}))
+	assert.Equal(t, "a or b", alternatives([]string{"a", "b"}))
+	assert.Equal(t, "a, b or c", alternatives([]string{"a", "b", "c"}))
+}
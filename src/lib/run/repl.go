@@ -0,0 +1,122 @@
+package run
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/compile"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/systemt"
+)
+
+const (
+	prompt             = "> "
+	continuationPrompt = "... "
+)
+
+// REPL runs a read-eval-print loop. It reads statements from r, prints values
+// of bare expressions into w, and runs effects.
+func REPL(r io.Reader, w io.Writer) error {
+	go systemt.RunDaemons()
+
+	i := compile.NewInterpreter()
+	b := bufio.NewReader(r)
+	src := ""
+
+	for {
+		p := prompt
+
+		if src != "" {
+			p = continuationPrompt
+		}
+
+		if _, err := fmt.Fprint(w, p); err != nil {
+			return err
+		}
+
+		l, err := b.ReadString('\n')
+		src += l
+
+		if err == io.EOF {
+			if strings.TrimSpace(src) != "" {
+				evalStatements(i, src, w)
+			}
+
+			_, err = fmt.Fprintln(w)
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		if !balanced(src) {
+			continue
+		}
+
+		evalStatements(i, src, w)
+		src = ""
+	}
+}
+
+func evalStatements(i *compile.Interpreter, src string, w io.Writer) {
+	es, err := i.Compile(src)
+
+	if err != nil {
+		printError(w, err)
+		return
+	}
+
+	for _, e := range es {
+		if e.Expanded() {
+			runEffects([]compile.Effect{e}, func(err error) { printError(w, err) })
+			continue
+		}
+
+		s, err := core.StrictDump(e.Value())
+
+		if err == nil {
+			fmt.Fprintln(w, s)
+		} else if err.(*core.ErrorType).Name() == "ImpureFunctionError" {
+			runEffects([]compile.Effect{e}, func(err error) { printError(w, err) })
+		} else {
+			printError(w, err.(*core.ErrorType))
+		}
+	}
+}
+
+func printError(w io.Writer, err error) {
+	fmt.Fprint(w, strings.TrimSpace(err.Error())+"\n")
+}
+
+// balanced returns true if all parentheses, brackets, braces, and string
+// literals in source code are closed.
+func balanced(s string) bool {
+	n := 0
+	inString, inComment, escaped := false, false, false
+
+	for _, r := range s {
+		switch {
+		case inComment:
+			inComment = r != '\n'
+		case inString:
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == '"' {
+				inString = false
+			}
+		case r == ';':
+			inComment = true
+		case r == '"':
+			inString = true
+		case strings.ContainsRune("([{", r):
+			n++
+		case strings.ContainsRune(")]}", r):
+			n--
+		}
+	}
+
+	return n <= 0 && !inString
+}
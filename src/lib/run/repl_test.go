@@ -0,0 +1,63 @@
+package run
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestREPL(t *testing.T) {
+	w := &bytes.Buffer{}
+
+	err := REPL(strings.NewReader(`(let x 42)
+x
+(def (f x)
+  (+ x 1))
+(f x)
+"foo"
+(print "bar")
+(+ 1 true)
+y
+(f`), w)
+
+	assert.Nil(t, err)
+
+	s := w.String()
+	t.Log(s)
+
+	for _, r := range []string{"42", "43", `"foo"`, "TypeError", "y is not found", continuationPrompt} {
+		assert.Contains(t, s, r)
+	}
+}
+
+func TestREPLWithInvalidEffectList(t *testing.T) {
+	w := &bytes.Buffer{}
+	c := make(chan error)
+
+	go func() {
+		c <- REPL(strings.NewReader("..42\n(+ 1 2)\n"), w)
+	}()
+
+	select {
+	case err := <-c:
+		assert.Nil(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("REPL did not return")
+	}
+
+	assert.Contains(t, w.String(), "Error")
+	assert.Contains(t, w.String(), "3")
+}
+
+func TestBalanced(t *testing.T) {
+	for _, s := range []string{"", "x", "(f x)", "[1 2] {3 4}", `"("`, "; (\n", "(f))"} {
+		assert.True(t, balanced(s))
+	}
+
+	for _, s := range []string{"(", "(f [x", `"foo`, `(f "\")`, "(f ; )\n"} {
+		assert.False(t, balanced(s))
+	}
+}
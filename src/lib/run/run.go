@@ -7,6 +7,7 @@ import (
 
 	"github.com/cloe-lang/cloe/src/lib/compile"
 	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
 	"github.com/cloe-lang/cloe/src/lib/systemt"
 )
 
@@ -17,7 +18,10 @@ var sem = make(chan bool, maxConcurrentEffects)
 // Run runs effects.
 func Run(os []compile.Effect) {
 	go systemt.RunDaemons()
+	runEffects(os, fail)
+}
 
+func runEffects(os []compile.Effect, fail func(error)) {
 	wg := sync.WaitGroup{}
 
 	for _, v := range os {
@@ -44,6 +48,7 @@ func evalEffectList(v core.Value, parent *sync.WaitGroup, fail func(error)) {
 	for {
 		if b, err := core.EvalBoolean(core.EvalPure(core.PApp(core.Equal, v, core.EmptyList))); err != nil {
 			fail(err.(*core.ErrorType))
+			return
 		} else if b {
 			break
 		}
@@ -71,7 +76,7 @@ var fail = failWithExit(os.Exit)
 
 func failWithExit(exit func(int)) func(error) {
 	return func(err error) {
-		_, err = fmt.Fprint(os.Stderr, err)
+		_, err = fmt.Fprint(os.Stderr, debug.Render(err, debug.ErrorFormat))
 
 		if err != nil {
 			panic(err)
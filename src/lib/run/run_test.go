@@ -17,18 +17,19 @@ func TestRunWithNoThunk(t *testing.T) {
 }
 
 func TestRunWithOneThunk(t *testing.T) {
-	Run([]compile.Effect{compile.NewEffect(core.PApp(builtins.Print, core.NewNumber(42)), false)})
+	Run([]compile.Effect{compile.NewEffect(core.PApp(builtins.Print, core.NewNumber(42)), false, nil)})
 }
 
 func TestRunWithThunks(t *testing.T) {
-	o := compile.NewEffect(core.PApp(builtins.Print, core.NewNumber(42)), false)
+	o := compile.NewEffect(core.PApp(builtins.Print, core.NewNumber(42)), false, nil)
 	Run([]compile.Effect{o, o, o, o, o, o, o, o})
 }
 
 func TestRunWithExpandedList(t *testing.T) {
 	Run([]compile.Effect{compile.NewEffect(
 		core.NewList(core.PApp(builtins.Print, core.True), core.PApp(builtins.Print, core.False)),
-		true)})
+		true,
+		nil)})
 }
 
 func TestEvalEffectListFail(t *testing.T) {
@@ -41,6 +42,13 @@ func TestEvalEffectListFail(t *testing.T) {
 	evalEffectList(core.DummyError, &wg, func(err error) { panic(err) })
 }
 
+func TestEvalEffectListFailWithoutExit(t *testing.T) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	evalEffectList(core.DummyError, &wg, func(error) {})
+	wg.Wait()
+}
+
 func TestRunEffectFail(t *testing.T) {
 	defer func() {
 		assert.NotNil(t, recover())
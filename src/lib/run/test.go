@@ -0,0 +1,117 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloe-lang/cloe/src/lib/compile"
+	"github.com/cloe-lang/cloe/src/lib/consts"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/systemt"
+)
+
+// Test runs tests in test files found in paths and reports their results into
+// w. Each top-level effect in the files is a test. It returns false if any
+// test fails.
+func Test(ps []string, w io.Writer) (bool, error) {
+	fs, err := testFiles(ps)
+
+	if err != nil {
+		return false, err
+	}
+
+	go systemt.RunDaemons()
+
+	passed, failed := 0, 0
+
+	for _, f := range fs {
+		es, err := compile.Compile(f)
+
+		if err != nil {
+			fmt.Fprintf(w, "FAIL\t%s\n", f)
+			printError(w, err)
+			failed++
+			continue
+		}
+
+		for _, e := range es {
+			if err := runTest(e); err != nil {
+				fmt.Fprintf(w, "FAIL\t%s", testLocation(e))
+				printError(w, err)
+				failed++
+				continue
+			}
+
+			fmt.Fprintf(w, "PASS\t%s", testLocation(e))
+			passed++
+		}
+	}
+
+	fmt.Fprintf(w, "%d passed, %d failed\n", passed, failed)
+
+	return failed == 0, nil
+}
+
+// testFiles finds test files in paths. Directories are searched recursively.
+func testFiles(ps []string) ([]string, error) {
+	fs := []string{}
+
+	for _, p := range ps {
+		err := filepath.Walk(p, func(p string, i os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			} else if !i.IsDir() && strings.HasSuffix(p, consts.TestFileSuffix) {
+				fs = append(fs, p)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fs, nil
+}
+
+// runTest runs a test of an effect. Effects in an expanded list are run one
+// by one and the first error stops the test.
+func runTest(e compile.Effect) error {
+	if !e.Expanded() {
+		if err, ok := core.EvalImpure(e.Value()).(*core.ErrorType); ok {
+			return err
+		}
+
+		return nil
+	}
+
+	v := e.Value()
+
+	for {
+		b, err := core.EvalBoolean(core.EvalPure(core.PApp(core.Equal, v, core.EmptyList)))
+
+		if err != nil {
+			return err.(*core.ErrorType)
+		} else if b {
+			return nil
+		}
+
+		if err, ok := core.EvalImpure(core.PApp(core.First, v)).(*core.ErrorType); ok {
+			return err
+		}
+
+		v = core.PApp(core.Rest, v)
+	}
+}
+
+func testLocation(e compile.Effect) string {
+	if i := e.DebugInfo(); i != nil {
+		return i.Lines()
+	}
+
+	return "<unknown>\n"
+}
@@ -0,0 +1,70 @@
+package run
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTest(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	for n, s := range map[string]string{
+		"foo_test.cloe": `(import "test")
+(test.assertEqual (+ 1 2) 3)
+(test.assertError (error "FooError" "foo") "FooError")
+..[(test.assertThrowsNothing 42) (test.assertEqual "foo" "foo")]`,
+		"main.cloe": `(print 42)`,
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(d, n), []byte(s), 0644))
+	}
+
+	w := &bytes.Buffer{}
+	ok, err := Test([]string{d}, w)
+
+	t.Log(w.String())
+
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.Contains(t, w.String(), "3 passed, 0 failed")
+}
+
+func TestTestFail(t *testing.T) {
+	d, err := ioutil.TempDir("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(d)
+
+	for n, s := range map[string]string{
+		"foo_test.cloe": `(import "test")
+(test.assertEqual 42 42)
+(test.assertEqual (+ 1 2) 4)
+..[(test.assertEqual 1 1) (test.assertThrowsNothing (error "FooError" "foo"))]`,
+		"bar_test.cloe": `(test.assertEqual 42 42)`,
+	} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(d, n), []byte(s), 0644))
+	}
+
+	w := &bytes.Buffer{}
+	ok, err := Test([]string{d}, w)
+
+	s := w.String()
+	t.Log(s)
+
+	assert.False(t, ok)
+	assert.Nil(t, err)
+
+	for _, r := range []string{"foo_test.cloe:3:", "AssertionError", "FooError", "1 passed, 3 failed"} {
+		assert.Contains(t, s, r)
+	}
+}
+
+func TestTestWithNonExistentPath(t *testing.T) {
+	_, err := Test([]string{"non-existent-path"}, &bytes.Buffer{})
+	assert.NotNil(t, err)
+}
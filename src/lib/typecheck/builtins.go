@@ -0,0 +1,214 @@
+package typecheck
+
+import "github.com/cloe-lang/cloe/src/lib/types"
+
+// builtin is a type of a built-in function whose overloaded signatures are
+// checked when it is applied directly.
+type builtin struct {
+	name      string
+	functions []*types.Function
+}
+
+func (*builtin) String() string {
+	return "function"
+}
+
+// value returns a type of a built-in function used as a value.
+func (b *builtin) value() types.Type {
+	if len(b.functions) == 1 {
+		return b.functions[0]
+	}
+
+	return types.AnyFunction
+}
+
+var (
+	elem = types.Variable("T")
+	key  = types.Variable("K")
+	val  = types.Variable("V")
+)
+
+var (
+	collection = types.Join(types.NewList(types.Any), types.NewDictionary(types.Any, types.Any), types.String)
+	ordered    = types.Join(types.Number, types.String, types.NewList(types.Any))
+)
+
+var builtins = map[string][]*types.Function{
+	"if":      {variadic(types.Any, types.Any)},
+	"partial": {types.NewFunction([]types.Type{types.AnyFunction}, types.Any, nil, true, types.AnyFunction)},
+
+	"first": {function(elem, list(elem))},
+	"rest":  {function(list(elem), list(elem))},
+
+	"typeOf":   {function(types.String, types.Any)},
+	"ordered?": {function(types.Boolean, types.Any)},
+
+	"+":   {variadic(types.Number, types.Number)},
+	"-":   {variadic(types.Number, types.Number, types.Number)},
+	"*":   {variadic(types.Number, types.Number)},
+	"/":   {variadic(types.Number, types.Number, types.Number)},
+	"//":  {variadic(types.Number, types.Number, types.Number)},
+	"mod": {function(types.Number, types.Number, types.Number)},
+	"**":  {function(types.Number, types.Number, types.Number)},
+
+	"=":  {variadic(types.Boolean, types.Any)},
+	"<":  {variadic(types.Boolean, ordered)},
+	"<=": {variadic(types.Boolean, ordered)},
+	">":  {variadic(types.Boolean, ordered)},
+	">=": {variadic(types.Boolean, ordered)},
+
+	"toString": {function(types.String, types.Any)},
+	"dump":     {function(types.String, types.Any)},
+
+	"@": {
+		function(elem, list(elem), types.Number),
+		function(val, dictionary(key, val), key),
+		function(types.String, types.String, types.Number),
+		variadic(types.Any, types.Any, collection, types.Any, types.Any),
+	},
+	"delete": {
+		function(list(elem), list(elem), types.Number),
+		function(dictionary(key, val), dictionary(key, val), key),
+		function(types.String, types.String, types.Number),
+	},
+	"include": {
+		function(types.Boolean, list(types.Any), types.Any),
+		function(types.Boolean, dictionary(types.Any, types.Any), types.Any),
+		function(types.Boolean, types.String, types.String),
+	},
+	"insert": {
+		function(list(elem), list(elem), types.Number, elem),
+		function(dictionary(key, val), dictionary(key, val), key, val),
+		function(types.String, types.String, types.Number, types.String),
+		variadic(types.Any, types.Any, collection, types.Any, types.Any, types.Any, types.Any),
+	},
+	"merge": {
+		variadic(list(elem), list(elem), list(elem)),
+		variadic(dictionary(key, val), dictionary(key, val), dictionary(key, val)),
+		variadic(types.String, types.String, types.String),
+	},
+	"size": {function(types.Number, collection)},
+	"toList": {
+		function(list(elem), list(elem)),
+		function(list(list(types.Any)), dictionary(types.Any, types.Any)),
+		function(list(types.String), types.String),
+	},
+
+	"par":   {variadic(types.Any, types.Any)},
+	"seq":   {variadic(types.Any, types.Any)},
+	"seq!":  {variadic(types.Any, types.Any)},
+	"rally": {variadic(types.Any, types.Any)},
+
+	"read": {types.NewFunction(
+		nil,
+		nil,
+		map[string]types.Type{"file": types.Join(types.String, types.Nil)},
+		false,
+		types.String)},
+	"print": {types.NewFunction(
+		nil,
+		types.Any,
+		map[string]types.Type{
+			"sep":  types.String,
+			"end":  types.String,
+			"file": types.Join(types.Number, types.String),
+			"mode": types.Number,
+		},
+		false,
+		types.Any)},
+
+	"error": {function(types.Any, types.String, types.String)},
+	"catch": {function(types.Any, types.Any)},
+
+	"pure": {function(types.Any, types.Any)},
+
+	"implement": {types.NewFunction(
+		[]types.Type{types.String},
+		nil,
+		map[string]types.Type{
+			"include":  types.AnyFunction,
+			"index":    types.AnyFunction,
+			"insert":   types.AnyFunction,
+			"merge":    types.AnyFunction,
+			"delete":   types.AnyFunction,
+			"toList":   types.AnyFunction,
+			"size":     types.AnyFunction,
+			"toString": types.AnyFunction,
+			"compare":  types.AnyFunction,
+		},
+		false,
+		types.Any)},
+
+	"boolean?":    {function(types.Boolean, types.Any)},
+	"dictionary?": {function(types.Boolean, types.Any)},
+	"function?":   {function(types.Boolean, types.Any)},
+	"list?":       {function(types.Boolean, types.Any)},
+	"nil?":        {function(types.Boolean, types.Any)},
+	"number?":     {function(types.Boolean, types.Any)},
+	"string?":     {function(types.Boolean, types.Any)},
+
+	"index": {types.NewFunction(
+		[]types.Type{list(types.Any), types.Any},
+		nil,
+		map[string]types.Type{"i": types.Number},
+		false,
+		types.Number)},
+	"map":    {function(list(types.Any), types.AnyFunction, list(types.Any))},
+	"reduce": {function(types.Any, types.AnyFunction, list(types.Any))},
+	"max":    {variadic(elem, elem)},
+	"min":    {variadic(elem, elem)},
+	"slice": {
+		types.NewFunction([]types.Type{list(elem)}, nil, slicing, false, list(elem)),
+		types.NewFunction([]types.Type{types.String}, nil, slicing, false, types.String),
+	},
+	"and":    {variadic(types.Boolean, types.Boolean)},
+	"or":     {variadic(types.Boolean, types.Boolean)},
+	"not":    {function(types.Boolean, types.Boolean)},
+	"zip":    {variadic(list(list(types.Any)), list(types.Any))},
+	"filter": {function(list(elem), types.AnyFunction, list(elem))},
+	"sort": {types.NewFunction(
+		[]types.Type{list(elem)},
+		nil,
+		map[string]types.Type{"less": types.AnyFunction},
+		false,
+		list(elem))},
+}
+
+// internalBuiltins are built-in functions used only by desugarers.
+var internalBuiltins = map[string][]*types.Function{
+	"list":       {variadic(list(elem), elem)},
+	"dictionary": {variadic(dictionary(types.Any, types.Any), types.Any)},
+}
+
+var slicing = map[string]types.Type{"start": types.Number, "end": types.Join(types.Number, types.Nil)}
+
+var builtinsEnvironment = func() *environment {
+	e := newEnvironment(nil)
+
+	for n, fs := range builtins {
+		e.set(n, &builtin{n, fs})
+		e.set("$"+n, &builtin{n, fs})
+	}
+
+	for n, fs := range internalBuiltins {
+		e.set("$"+n, &builtin{n, fs})
+	}
+
+	return e
+}()
+
+func function(r types.Type, ps ...types.Type) *types.Function {
+	return types.NewFunction(ps, nil, nil, false, r)
+}
+
+func variadic(r, rest types.Type, ps ...types.Type) *types.Function {
+	return types.NewFunction(ps, rest, nil, false, r)
+}
+
+func list(t types.Type) types.Type {
+	return types.NewList(t)
+}
+
+func dictionary(k, v types.Type) types.Type {
+	return types.NewDictionary(k, v)
+}
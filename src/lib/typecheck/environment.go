@@ -0,0 +1,27 @@
+package typecheck
+
+import "github.com/cloe-lang/cloe/src/lib/types"
+
+// environment maps names to types in a lexical scope.
+type environment struct {
+	parent *environment
+	types  map[string]types.Type
+}
+
+func newEnvironment(p *environment) *environment {
+	return &environment{p, map[string]types.Type{}}
+}
+
+func (e *environment) get(n string) types.Type {
+	for ; e != nil; e = e.parent {
+		if t, ok := e.types[n]; ok {
+			return t
+		}
+	}
+
+	return nil
+}
+
+func (e *environment) set(n string, t types.Type) {
+	e.types[n] = t
+}
@@ -0,0 +1,52 @@
+package typecheck
+
+import "github.com/cloe-lang/cloe/src/lib/types"
+
+// Modules maps names of built-in modules to types of their members.
+var Modules = map[string]map[string]types.Type{
+	"fs": {
+		"createDirectory": types.NewFunction(
+			[]types.Type{types.String},
+			nil,
+			map[string]types.Type{"existOk": types.Boolean},
+			false,
+			types.Any),
+		"readDirectory": function(list(types.String), types.String),
+		"remove":        function(types.Any, types.String),
+	},
+	"http": {
+		"get": types.NewFunction(
+			[]types.Type{types.String},
+			nil,
+			map[string]types.Type{"error": types.Boolean},
+			false,
+			dictionary(types.String, types.Any)),
+		"getRequests": function(types.Any, types.String),
+		"post": types.NewFunction(
+			[]types.Type{types.String, types.String},
+			nil,
+			map[string]types.Type{"contentType": types.String, "error": types.Boolean},
+			false,
+			dictionary(types.String, types.Any)),
+	},
+	"json": {
+		"decode": function(types.Any, types.String),
+		"encode": function(types.String, types.Any),
+	},
+	"os": {
+		"exit": types.NewFunction(nil, nil, map[string]types.Type{"status": types.Number}, false, types.Any),
+	},
+	"random": {
+		"number": function(types.Number),
+	},
+	"re": {
+		"find":    function(types.Join(list(types.Join(types.String, types.Nil)), types.Nil), types.String, types.String),
+		"match":   function(types.Boolean, types.String, types.String),
+		"replace": function(types.String, types.String, types.String, types.String),
+	},
+	"test": {
+		"assertEqual":         function(types.Any, types.Any, types.Any),
+		"assertError":         function(types.Any, types.Any, types.String),
+		"assertThrowsNothing": function(types.Any, types.Any),
+	},
+}
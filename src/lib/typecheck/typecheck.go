@@ -0,0 +1,438 @@
+// Package typecheck checks types of values statically against type
+// annotations of functions and signatures of built-in functions.
+package typecheck
+
+import (
+	"github.com/cloe-lang/cloe/src/lib/ast"
+	"github.com/cloe-lang/cloe/src/lib/core"
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/scalar"
+	"github.com/cloe-lang/cloe/src/lib/types"
+)
+
+// Check checks types in a module of statements desugared by
+// desugar.DesugarSyntax. Types of names in imported modules are given as maps
+// indexed by import paths. It returns types of names defined in the module.
+func Check(ss []interface{}, ms map[string]map[string]types.Type) (map[string]types.Type, error) {
+	c := checker{modules: ms}
+	e := newEnvironment(newEnvironment(builtinsEnvironment))
+
+	c.statements(e, ss)
+
+	if len(c.errors) != 0 {
+		return nil, debug.Errors(c.errors)
+	}
+
+	return e.types, nil
+}
+
+type checker struct {
+	modules map[string]map[string]types.Type
+	errors  []error
+}
+
+func (c *checker) statements(e *environment, ss []interface{}) {
+	for _, s := range ss {
+		switch s := s.(type) {
+		case ast.LetVar:
+			e.set(s.Name(), types.Any)
+		case ast.DefFunction:
+			e.set(s.Name(), functionType(s.Signature()))
+		case ast.MutualRecursion:
+			for _, f := range s.DefFunctions() {
+				e.set(f.Name(), functionType(f.Signature()))
+			}
+		}
+	}
+
+	for _, s := range ss {
+		c.statement(e, s)
+	}
+}
+
+func (c *checker) statement(e *environment, s interface{}) {
+	switch s := s.(type) {
+	case ast.Import:
+		for n, t := range c.modules[s.Path()] {
+			if l, ok := s.LocalName(n); ok {
+				e.parent.set(l, t)
+			}
+		}
+	case ast.LetVar:
+		e.set(s.Name(), c.expression(e, s.Expr()))
+	case ast.DefFunction:
+		c.defFunction(e, s)
+	case ast.MutualRecursion:
+		for _, f := range s.DefFunctions() {
+			c.defFunction(e, f)
+		}
+	case ast.Effect:
+		c.expression(e, s.Expr())
+	}
+}
+
+func (c *checker) defFunction(e *environment, f ast.DefFunction) {
+	s := f.Signature()
+	e = newEnvironment(e)
+
+	for _, n := range s.Positionals() {
+		e.set(n, annotation(s, n, types.Any))
+	}
+
+	if n := s.RestPositionals(); n != "" {
+		e.set(n, annotation(s, n, types.NewList(types.Any)))
+	}
+
+	for _, k := range s.Keywords() {
+		t := annotation(s, k.Name(), types.Any)
+
+		if k.Required() {
+			e.set(k.Name(), t)
+			continue
+		}
+
+		if u := c.expression(e.parent, k.DefaultValue()); !types.Consistent(u, t) {
+			c.addError(
+				f.DebugInfo(),
+				"default value of %s in %s must be %s but got %s",
+				k.Name(), f.Name(), t, u)
+		}
+
+		e.set(k.Name(), t)
+	}
+
+	if n := s.RestKeywords(); n != "" {
+		e.set(n, annotation(s, n, types.NewDictionary(types.String, types.Any)))
+	}
+
+	c.statements(e, f.Lets())
+
+	if t, u := s.Result(), c.expression(e, f.Body()); t != nil && !types.Consistent(u, t) {
+		c.addError(f.DebugInfo(), "function %s returns %s instead of %s", f.Name(), u, t)
+	}
+}
+
+func (c *checker) expression(e *environment, x interface{}) types.Type {
+	switch x := x.(type) {
+	case string:
+		if v, err := scalar.Convert(x); err == nil {
+			return valueType(v)
+		}
+
+		switch t := e.get(x).(type) {
+		case nil:
+			return types.Any
+		case *builtin:
+			return t.value()
+		default:
+			return t
+		}
+	case ast.App:
+		return c.app(e, x)
+	case ast.Switch:
+		c.expression(e, x.Value())
+		ts := []types.Type{c.expression(e, x.DefaultCase())}
+
+		for _, k := range x.Cases() {
+			ts = append(ts, c.expression(e, k.Value()))
+		}
+
+		return types.Join(ts...)
+	}
+
+	return types.Any
+}
+
+func (c *checker) app(e *environment, a ast.App) types.Type {
+	ps := []types.Type{}
+
+	for _, p := range a.Arguments().Positionals() {
+		ps = append(ps, c.expression(e, p.Value()))
+	}
+
+	ks := []types.Type{}
+
+	for _, k := range a.Arguments().Keywords() {
+		ks = append(ks, c.expression(e, k.Value()))
+	}
+
+	if n, ok := a.Function().(string); ok {
+		if b, ok := e.get(n).(*builtin); ok {
+			switch b.name {
+			case "if":
+				return c.ifApp(a, ps)
+			case "dictionary":
+				return dictionaryType(a, ps)
+			}
+
+			return c.apply(b.name, b.functions, a, ps, ks)
+		}
+	}
+
+	n, _ := a.Function().(string)
+
+	if scalar.Defined(n) {
+		n = ""
+	}
+
+	switch f := c.expression(e, a.Function()).(type) {
+	case *types.Function:
+		if n == "" {
+			n = "function"
+		}
+
+		return c.apply(n, []*types.Function{f}, a, ps, ks)
+	default:
+		if types.Consistent(f, types.AnyFunction) {
+			break
+		} else if n == "" {
+			c.addError(a.DebugInfo(), "%s is not a function", f)
+		} else {
+			c.addError(a.DebugInfo(), "%s is %s but not a function", n, f)
+		}
+	}
+
+	return types.Any
+}
+
+// apply checks types of arguments passed to overloaded functions and returns
+// a type of results.
+func (c *checker) apply(n string, fs []*types.Function, a ast.App, ps, ks []types.Type) types.Type {
+	m := len(ps)
+
+	for i, p := range a.Arguments().Positionals() {
+		if p.Expanded() {
+			m = i
+			break
+		}
+	}
+
+	expanded := m != len(ps)
+
+	fs = filterFunctions(fs, func(f *types.Function) bool {
+		l, r := len(f.Positionals()), f.RestPositionals() != nil
+
+		if expanded {
+			return m <= l || r
+		}
+
+		return m == l || m > l && r
+	})
+
+	if len(fs) == 0 {
+		c.addError(a.DebugInfo(), "%s cannot take %d arguments", n, m)
+		return types.Any
+	}
+
+	for i, t := range ps[:m] {
+		gs := filterFunctions(fs, func(f *types.Function) bool {
+			return types.Consistent(t, positionalType(f, i))
+		})
+
+		if len(gs) == 0 {
+			us := make([]types.Type, 0, len(fs))
+
+			for _, f := range fs {
+				us = append(us, types.Bindings{}.Substitute(positionalType(f, i)))
+			}
+
+			c.addError(
+				a.DebugInfo(),
+				"argument %d of %s must be %s but got %s",
+				i+1, n, types.Join(us...), t)
+			return types.Any
+		}
+
+		fs = gs
+	}
+
+	for i, k := range a.Arguments().Keywords() {
+		if k.Name() == "" {
+			break
+		}
+
+		gs := filterFunctions(fs, func(f *types.Function) bool {
+			t, ok := f.Keyword(k.Name())
+			return ok && types.Consistent(ks[i], t)
+		})
+
+		if len(gs) == 0 {
+			if t := keywordType(fs, k.Name()); t == nil {
+				c.addError(a.DebugInfo(), "%s has no keyword parameter %s", n, k.Name())
+			} else {
+				c.addError(
+					a.DebugInfo(),
+					"keyword argument %s of %s must be %s but got %s",
+					k.Name(), n, t, ks[i])
+			}
+
+			return types.Any
+		}
+
+		fs = gs
+	}
+
+	ts := make([]types.Type, 0, len(fs))
+
+	for _, f := range fs {
+		bs := types.Bindings{}
+
+		for i, t := range ps {
+			if i < m {
+				bs.Bind(positionalType(f, i), t)
+			} else if r := f.RestPositionals(); r == nil {
+				break
+			} else if !a.Arguments().Positionals()[i].Expanded() {
+				bs.Bind(r, t)
+			} else if l, ok := t.(*types.List); ok {
+				bs.Bind(r, l.Element())
+			} else {
+				bs.Bind(r, types.Any)
+			}
+		}
+
+		ts = append(ts, bs.Substitute(f.Result()))
+	}
+
+	return types.Join(ts...)
+}
+
+// ifApp checks conditions of an application of the if function and returns a
+// union of types of its values.
+func (c *checker) ifApp(a ast.App, ps []types.Type) types.Type {
+	if len(ps)%2 == 0 {
+		c.addError(a.DebugInfo(), "if cannot take %d arguments", len(ps))
+		return types.Any
+	}
+
+	ts := []types.Type{ps[len(ps)-1]}
+
+	for i := 0; i < len(ps)-1; i += 2 {
+		if a.Arguments().Positionals()[i].Expanded() {
+			return types.Any
+		} else if !types.Consistent(ps[i], types.Boolean) {
+			c.addError(a.DebugInfo(), "argument %d of if must be boolean but got %s", i+1, ps[i])
+		}
+
+		ts = append(ts, ps[i+1])
+	}
+
+	return types.Join(ts...)
+}
+
+func dictionaryType(a ast.App, ps []types.Type) types.Type {
+	ks, vs := []types.Type{}, []types.Type{}
+
+	for i, p := range a.Arguments().Positionals() {
+		if p.Expanded() {
+			return types.NewDictionary(types.Any, types.Any)
+		} else if i%2 == 0 {
+			ks = append(ks, ps[i])
+		} else {
+			vs = append(vs, ps[i])
+		}
+	}
+
+	return types.NewDictionary(types.Join(ks...), types.Join(vs...))
+}
+
+func (c *checker) addError(i *debug.Info, m string, xs ...interface{}) {
+	c.errors = append(c.errors, debug.NewError(i, "TypeError", m, xs...))
+}
+
+func functionType(s ast.Signature) *types.Function {
+	ps := make([]types.Type, 0, len(s.Positionals()))
+
+	for _, n := range s.Positionals() {
+		ps = append(ps, annotation(s, n, types.Any))
+	}
+
+	var r types.Type
+
+	if n := s.RestPositionals(); n != "" {
+		r = types.Any
+
+		if l, ok := s.Type(n).(*types.List); ok {
+			r = l.Element()
+		}
+	}
+
+	ks := make(map[string]types.Type, len(s.Keywords()))
+
+	for _, k := range s.Keywords() {
+		ks[k.Name()] = annotation(s, k.Name(), types.Any)
+	}
+
+	return types.NewFunction(ps, r, ks, s.RestKeywords() != "", annotation(s, "", types.Any))
+}
+
+// annotation returns a type annotation of a parameter or a result of a
+// function if a name is empty. It returns a default type if it is missing.
+func annotation(s ast.Signature, n string, d types.Type) types.Type {
+	t := s.Type(n)
+
+	if n == "" {
+		t = s.Result()
+	}
+
+	if t == nil {
+		return d
+	}
+
+	return t
+}
+
+func positionalType(f *types.Function, i int) types.Type {
+	if i < len(f.Positionals()) {
+		return f.Positionals()[i]
+	}
+
+	return f.RestPositionals()
+}
+
+func keywordType(fs []*types.Function, n string) types.Type {
+	ts := []types.Type{}
+
+	for _, f := range fs {
+		if t, ok := f.Keyword(n); ok {
+			ts = append(ts, types.Bindings{}.Substitute(t))
+		}
+	}
+
+	if len(ts) == 0 {
+		return nil
+	}
+
+	return types.Join(ts...)
+}
+
+func filterFunctions(fs []*types.Function, p func(*types.Function) bool) []*types.Function {
+	gs := []*types.Function{}
+
+	for _, f := range fs {
+		if p(f) {
+			gs = append(gs, f)
+		}
+	}
+
+	return gs
+}
+
+func valueType(v core.Value) types.Type {
+	switch v.(type) {
+	case *core.BooleanType:
+		return types.Boolean
+	case *core.DictionaryType:
+		return types.NewDictionary(types.Any, types.Any)
+	case *core.ListType:
+		return types.NewList(types.Any)
+	case core.NilType:
+		return types.Nil
+	case *core.NumberType:
+		return types.Number
+	case core.StringType:
+		return types.String
+	}
+
+	return types.Any
+}
@@ -0,0 +1,159 @@
+package typecheck
+
+import (
+	"testing"
+
+	"github.com/cloe-lang/cloe/src/lib/debug"
+	"github.com/cloe-lang/cloe/src/lib/desugar"
+	"github.com/cloe-lang/cloe/src/lib/parse"
+	"github.com/cloe-lang/cloe/src/lib/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func check(s string, ms map[string]map[string]types.Type) (map[string]types.Type, error) {
+	m, err := parse.MainModule("main.cloe", s)
+
+	if err != nil {
+		panic(err)
+	}
+
+	m, err = desugar.DesugarSyntax(m, nil)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return Check(m, ms)
+}
+
+func TestCheck(t *testing.T) {
+	for _, s := range []string{
+		`(print (+ 1 2))`,
+		`(def (f x:number) :number (+ x 1)) (print (f 42))`,
+		`(def (f x) (+ x 1)) (print (f "foo"))`,
+		`(def (f x:number|nil) x) (print (f nil) (f 1))`,
+		`(def (f ..xs:list[number]) (+ ..xs)) (print (f 1 2 ..[3]))`,
+		`(def (f . x:string "foo") x) (print (f . x "bar"))`,
+		`(def (f x:list[number]) :number (first x)) (print (f [1 2]))`,
+		`(def (f x:dictionary[string number]) :number (@ x "foo")) (print (f {"foo" 1}))`,
+		`(def (f x:string) :string (merge x "bar")) (print (f "foo"))`,
+		`(def (f x:list[number]) :list[number] (insert x 1 2))`,
+		`(def (f x:function) (x 1)) (print (f f) (f (\ (x) x)))`,
+		`(def (f) :number (g)) (def (g) :number 42)`,
+		`(def (f x) :string (if x "foo" "bar"))`,
+		`(def (f x) :number|string (if x 1 "bar"))`,
+		`(def (f x) :number (match x [y ..ys] y _ 0))`,
+		`(let x 42) (def (f) :number x)`,
+		`(def (f x) (let g (\ (y:number) (+ x y))) (g 1))`,
+		`(import "re") (def (f x:string) :boolean (re.match "a" x))`,
+		`(print (+ ..[1 2]))`,
+		`(print (f 1)) (def (f x:number) x)`,
+	} {
+		_, err := check(s, Modules)
+		assert.Nil(t, err, s)
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	for _, c := range []struct {
+		source, message string
+		line, column    int
+	}{
+		{`(print (+ 1 "foo"))`, "argument 2 of + must be number but got string", 1, 8},
+		{`(print (- "foo"))`, "argument 1 of - must be number but got string", 1, 8},
+		{`(def (f x:number) x) (print (f "foo"))`, "argument 1 of f must be number but got string", 1, 29},
+		{`(def (f x:number) x)
+(print (f 1 2))`, "f cannot take 2 arguments", 2, 8},
+		{`(def (f x:number) x) (print (f))`, "f cannot take 0 arguments", 1, 29},
+		{`(def (f) :string 42)`, "function f returns number instead of string", 1, 1},
+		{`(def (f x:number) :string (+ x 1))`, "function f returns number instead of string", 1, 1},
+		{`(def (f . x:number "foo") x)`, "default value of x in f must be number but got string", 1, 1},
+		{`(def (f . x:number 0) x) (print (f . x "foo"))`, "keyword argument x of f must be number but got string", 1, 33},
+		{`(def (f . x 0) x) (print (f . y 1))`, "f has no keyword parameter y", 1, 26},
+		{`(def (f ..xs:list[number]) xs) (print (f 1 "foo"))`, "argument 2 of f must be number but got string", 1, 39},
+		{`(def (f x:number|nil) x) (print (f "foo"))`, "argument 1 of f must be number|nil but got string", 1, 33},
+		{`(def (f x:list[number]) x) (print (f ["foo"]))`, "argument 1 of f must be list[number] but got list[string]", 1, 35},
+		{`(print (merge "foo" [1]))`, "argument 2 of merge must be string but got list[number]", 1, 8},
+		{`(print (merge {"a" 1} [1]))`, "argument 2 of merge must be dictionary but got list[number]", 1, 8},
+		{`(print (@ 42 1))`, "argument 1 of @ must be list|dictionary|string but got number", 1, 8},
+		{`(print (@ [1 2] "foo"))`, "argument 2 of @ must be number but got string", 1, 8},
+		{`(print (insert "foo" 1 2))`, "argument 3 of insert must be string but got number", 1, 8},
+		{`(print (if 1 2 3))`, "argument 1 of if must be boolean but got number", 1, 8},
+		{`(print (42 1))`, "number is not a function", 1, 8},
+		{`(let x "foo") (print (x 1))`, "x is string but not a function", 1, 22},
+		{`(def (f x:number) (x 1))`, "x is number but not a function", 1, 19},
+		{`(def (f) :number (let x "foo") x)`, "function f returns string instead of number", 1, 1},
+		{`(def (f x:list[number]) :string (first x))`, "function f returns number instead of string", 1, 1},
+		{`(def (f x:number) (match x 1 2 _ 3)) (print (f "foo"))`, "argument 1 of f must be number but got string", 1, 45},
+		{`(import "re") (print (re.match 1 "foo"))`, "argument 1 of re.match must be string but got number", 1, 22},
+		{`(print (print . sep 1))`, "keyword argument sep of print must be string but got number", 1, 8},
+		{`(print (+ 1 (\ (x) x)))`, "argument 2 of + must be number but got function", 1, 8},
+		{`(print (implement "foo" . length (\ () 0)))`, "implement has no keyword parameter length", 1, 8},
+		{`(print (implement "foo" . size 0))`, "keyword argument size of implement must be function but got number", 1, 8},
+	} {
+		_, err := check(c.source, Modules)
+
+		es, ok := err.(debug.Errors)
+		assert.True(t, ok, c.source)
+
+		if !ok {
+			continue
+		}
+
+		e := es[0].(*debug.Error)
+		assert.Equal(t, "TypeError", e.Name())
+		assert.Equal(t, c.message, e.Message())
+		assert.Equal(t, c.line, e.Info().LineNumber())
+		assert.Equal(t, c.column, e.Info().LinePosition())
+	}
+}
+
+func TestCheckMultipleErrors(t *testing.T) {
+	_, err := check(`(def (f x:number) :string x) (print (f "foo") (+ 1 nil))`, nil)
+
+	es, ok := err.(debug.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 3, len(es))
+}
+
+func TestCheckWithImportedModules(t *testing.T) {
+	ts, err := check(`(def (f x:number) :string (toString x)) (let y 42)`, nil)
+	assert.Nil(t, err)
+
+	f, ok := ts["f"].(*types.Function)
+	assert.True(t, ok)
+	assert.Equal(t, []types.Type{types.Number}, f.Positionals())
+	assert.Equal(t, types.String, f.Result())
+	assert.Equal(t, types.Number, ts["y"])
+
+	ms := map[string]map[string]types.Type{"./foo": ts}
+
+	for _, c := range []struct {
+		source string
+		ok     bool
+	}{
+		{`(import "./foo") (print (foo.f 42) (+ foo.y 1))`, true},
+		{`(import "./foo") (print (foo.f "bar"))`, false},
+		{`(import "./foo") (print (merge foo.y "bar"))`, false},
+		{`(import bar "./foo") (print (bar.f "bar"))`, false},
+		{`(import . "./foo") (print (f "bar"))`, false},
+		{`(import . "./foo") (def (f x) x) (print (f "bar"))`, true},
+		{`(import "./foo" (f)) (print (f "bar"))`, false},
+		{`(import "./foo" (f . as g)) (print (g "bar"))`, false},
+		{`(import "./foo" (y)) (print (f "bar"))`, true},
+	} {
+		_, err := check(c.source, ms)
+		assert.Equal(t, c.ok, err == nil, c.source)
+	}
+}
+
+func TestCheckShadowedBuiltins(t *testing.T) {
+	for _, s := range []string{
+		`(def (+ x y) (merge x y)) (print (+ "foo" "bar"))`,
+		`(def (f first) (first 1)) (print (f (\ (x) x)))`,
+		`(let if 42) (print (+ if 1))`,
+	} {
+		_, err := check(s, nil)
+		assert.Nil(t, err, s)
+	}
+}
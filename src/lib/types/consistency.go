@@ -0,0 +1,95 @@
+package types
+
+// Consistent checks if values of a type can be used as ones of another type.
+// Any and type variables are consistent with every type. Unions are consistent
+// with types when any of their members are so that only values which never
+// have expected types are rejected.
+func Consistent(t, u Type) bool {
+	if isDynamic(t) || isDynamic(u) {
+		return true
+	}
+
+	for _, t := range members(t) {
+		for _, u := range members(u) {
+			if consistent(t, u) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func consistent(t, u Type) bool {
+	switch x := t.(type) {
+	case basic:
+		return x == u
+	case *List:
+		y, ok := u.(*List)
+		return ok && Consistent(x.element, y.element)
+	case *Dictionary:
+		y, ok := u.(*Dictionary)
+		return ok && Consistent(x.key, y.key) && Consistent(x.value, y.value)
+	case *Function:
+		_, ok := u.(*Function)
+		return ok
+	}
+
+	return false
+}
+
+func isDynamic(t Type) bool {
+	_, ok := t.(Variable)
+	return ok || t == Any
+}
+
+// Bindings maps type variables to types.
+type Bindings map[Variable]Type
+
+// Bind binds type variables in a type to corresponding types in another type.
+func (bs Bindings) Bind(t, u Type) {
+	switch x := t.(type) {
+	case Variable:
+		if v, ok := bs[x]; ok {
+			u = Join(v, u)
+		}
+
+		bs[x] = u
+	case *List:
+		if y, ok := u.(*List); ok {
+			bs.Bind(x.element, y.element)
+		}
+	case *Dictionary:
+		if y, ok := u.(*Dictionary); ok {
+			bs.Bind(x.key, y.key)
+			bs.Bind(x.value, y.value)
+		}
+	}
+}
+
+// Substitute replaces type variables in a type with bound types. Unbound
+// type variables are replaced with Any.
+func (bs Bindings) Substitute(t Type) Type {
+	switch x := t.(type) {
+	case Variable:
+		if u, ok := bs[x]; ok {
+			return u
+		}
+
+		return Any
+	case *List:
+		return NewList(bs.Substitute(x.element))
+	case *Dictionary:
+		return NewDictionary(bs.Substitute(x.key), bs.Substitute(x.value))
+	case *Union:
+		ts := make([]Type, 0, len(x.types))
+
+		for _, t := range x.types {
+			ts = append(ts, bs.Substitute(t))
+		}
+
+		return Join(ts...)
+	}
+
+	return t
+}
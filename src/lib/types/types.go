@@ -0,0 +1,208 @@
+// Package types represents types of values checked statically.
+package types
+
+import "strings"
+
+// Type represents a type of values.
+type Type interface {
+	String() string
+}
+
+type basic string
+
+func (b basic) String() string {
+	return string(b)
+}
+
+var (
+	// Any is a type of values whose types are checked only at runtime.
+	Any Type = basic("any")
+
+	// Boolean is a type of booleans.
+	Boolean Type = basic("boolean")
+
+	// Nil is a type of nil.
+	Nil Type = basic("nil")
+
+	// Number is a type of numbers.
+	Number Type = basic("number")
+
+	// String is a type of strings.
+	String Type = basic("string")
+)
+
+// List is a type of lists.
+type List struct {
+	element Type
+}
+
+// NewList creates a type of lists of elements of a given type.
+func NewList(t Type) *List {
+	return &List{t}
+}
+
+// Element returns a type of elements in lists.
+func (l *List) Element() Type {
+	return l.element
+}
+
+func (l *List) String() string {
+	if l.element == Any {
+		return "list"
+	}
+
+	return "list[" + l.element.String() + "]"
+}
+
+// Dictionary is a type of dictionaries.
+type Dictionary struct {
+	key, value Type
+}
+
+// NewDictionary creates a type of dictionaries of keys and values of given
+// types.
+func NewDictionary(k, v Type) *Dictionary {
+	return &Dictionary{k, v}
+}
+
+// Key returns a type of keys in dictionaries.
+func (d *Dictionary) Key() Type {
+	return d.key
+}
+
+// Value returns a type of values in dictionaries.
+func (d *Dictionary) Value() Type {
+	return d.value
+}
+
+func (d *Dictionary) String() string {
+	if d.key == Any && d.value == Any {
+		return "dictionary"
+	}
+
+	return "dictionary[" + d.key.String() + " " + d.value.String() + "]"
+}
+
+// Function is a type of functions.
+type Function struct {
+	positionals     []Type
+	restPositionals Type
+	keywords        map[string]Type
+	restKeywords    bool
+	result          Type
+}
+
+// NewFunction creates a type of functions from types of positional parameters,
+// elements of a positional rest parameter, keyword parameters and a result.
+// A nil rest type means that functions have no positional rest parameter.
+func NewFunction(ps []Type, pr Type, ks map[string]Type, kr bool, r Type) *Function {
+	return &Function{ps, pr, ks, kr, r}
+}
+
+// AnyFunction is a type of functions whose signatures are unknown.
+var AnyFunction = NewFunction(nil, Any, nil, true, Any)
+
+// Positionals returns types of positional parameters.
+func (f *Function) Positionals() []Type {
+	return f.positionals
+}
+
+// RestPositionals returns a type of elements of a positional rest parameter
+// or nil if functions have no positional rest parameter.
+func (f *Function) RestPositionals() Type {
+	return f.restPositionals
+}
+
+// Keyword returns a type of a keyword parameter.
+func (f *Function) Keyword(n string) (Type, bool) {
+	if t, ok := f.keywords[n]; ok {
+		return t, true
+	} else if f.restKeywords {
+		return Any, true
+	}
+
+	return nil, false
+}
+
+// Result returns a type of results.
+func (f *Function) Result() Type {
+	return f.result
+}
+
+func (f *Function) String() string {
+	return "function"
+}
+
+// Union is a type of values of any of its member types.
+type Union struct {
+	types []Type
+}
+
+// Types returns member types of a union.
+func (u *Union) Types() []Type {
+	return u.types
+}
+
+func (u *Union) String() string {
+	ss := make([]string, 0, len(u.types))
+
+	for _, t := range u.types {
+		ss = append(ss, t.String())
+	}
+
+	return strings.Join(ss, "|")
+}
+
+// Join creates a union of types. It returns Any when no type is given.
+func Join(ts ...Type) Type {
+	us := []Type{}
+
+	for _, t := range ts {
+		for _, t := range members(t) {
+			if t == Any {
+				return Any
+			} else if !contains(us, t) {
+				us = append(us, t)
+			}
+		}
+	}
+
+	switch len(us) {
+	case 0:
+		return Any
+	case 1:
+		return us[0]
+	}
+
+	return &Union{us}
+}
+
+func members(t Type) []Type {
+	if u, ok := t.(*Union); ok {
+		return u.types
+	}
+
+	return []Type{t}
+}
+
+func contains(ts []Type, t Type) bool {
+	for _, u := range ts {
+		if Equal(t, u) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Equal checks if two types are the same.
+func Equal(t, u Type) bool {
+	return t.String() == u.String()
+}
+
+// Variable is a type variable in signatures of polymorphic functions.
+type Variable string
+
+func (v Variable) String() string {
+	return string(v)
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeString(t *testing.T) {
+	for _, c := range []struct {
+		typ    Type
+		string string
+	}{
+		{Number, "number"},
+		{NewList(Any), "list"},
+		{NewList(String), "list[string]"},
+		{NewDictionary(Any, Any), "dictionary"},
+		{NewDictionary(String, NewList(Number)), "dictionary[string list[number]]"},
+		{AnyFunction, "function"},
+		{Join(Number, Nil), "number|nil"},
+		{Variable("T"), "T"},
+	} {
+		assert.Equal(t, c.string, c.typ.String())
+	}
+}
+
+func TestJoin(t *testing.T) {
+	for _, c := range []struct {
+		types  []Type
+		string string
+	}{
+		{nil, "any"},
+		{[]Type{Number}, "number"},
+		{[]Type{Number, Number}, "number"},
+		{[]Type{Number, String, Number}, "number|string"},
+		{[]Type{Join(Number, String), Join(String, Nil)}, "number|string|nil"},
+		{[]Type{NewList(Number), NewList(Number)}, "list[number]"},
+		{[]Type{Number, Any}, "any"},
+	} {
+		assert.Equal(t, c.string, Join(c.types...).String())
+	}
+}
+
+func TestFunctionKeyword(t *testing.T) {
+	f := NewFunction(nil, nil, map[string]Type{"x": Number}, false, Any)
+
+	k, ok := f.Keyword("x")
+	assert.True(t, ok)
+	assert.Equal(t, Number, k)
+
+	_, ok = f.Keyword("y")
+	assert.False(t, ok)
+
+	k, ok = AnyFunction.Keyword("y")
+	assert.True(t, ok)
+	assert.Equal(t, Any, k)
+}
+
+func TestConsistent(t *testing.T) {
+	for _, c := range []struct {
+		from, to Type
+	}{
+		{Number, Number},
+		{Number, Any},
+		{Any, Number},
+		{Number, Variable("T")},
+		{Number, Join(Number, Nil)},
+		{Join(Number, Nil), Nil},
+		{NewList(Number), NewList(Any)},
+		{NewList(Any), NewList(String)},
+		{NewDictionary(String, Number), NewDictionary(String, Join(Number, String))},
+		{NewFunction([]Type{Number}, nil, nil, false, Number), AnyFunction},
+	} {
+		assert.True(t, Consistent(c.from, c.to))
+	}
+
+	for _, c := range []struct {
+		from, to Type
+	}{
+		{Number, String},
+		{Nil, Boolean},
+		{String, Join(Number, Nil)},
+		{Join(Number, Nil), String},
+		{NewList(Number), NewList(String)},
+		{NewList(Number), NewDictionary(Any, Any)},
+		{NewDictionary(String, Number), NewDictionary(Number, Number)},
+		{Number, AnyFunction},
+		{AnyFunction, NewList(Any)},
+	} {
+		assert.False(t, Consistent(c.from, c.to))
+	}
+}
+
+func TestBindings(t *testing.T) {
+	v := Variable("T")
+	bs := Bindings{}
+
+	bs.Bind(NewList(v), NewList(Number))
+	bs.Bind(v, String)
+	bs.Bind(NewDictionary(Variable("K"), v), NewList(Nil))
+
+	assert.Equal(t, "number|string", bs.Substitute(v).String())
+	assert.Equal(t, "list[number|string]", bs.Substitute(NewList(v)).String())
+	assert.Equal(t, "dictionary[any number|string]", bs.Substitute(NewDictionary(Variable("K"), v)).String())
+	assert.Equal(t, "number|string|nil", bs.Substitute(Join(v, Nil)).String())
+}